@@ -4,31 +4,59 @@ import (
 	"strings"
 	"github.com/vynazevedo/termx/renderer"
 	"github.com/vynazevedo/termx/theme"
+	"github.com/vynazevedo/termx/textwidth"
 )
 
 type Art struct {
-	content []string
-	color   string
+	content  []string
+	color    string
+	renderer *renderer.Renderer
+	theme    *theme.Theme
 }
 
 func New(art string) *Art {
 	return &Art{
 		content: strings.Split(art, "\n"),
-		color:   theme.Current().Primary.Foreground,
 	}
 }
 
+// WithColor draws the art in color, an SGR sequence, instead of the primary
+// color of the theme
 func (a *Art) WithColor(color string) *Art {
 	a.color = color
 	return a
 }
 
+// WithTheme draws the art with t instead of theme.Current()
+func (a *Art) WithTheme(t *theme.Theme) *Art {
+	a.theme = t
+	return a
+}
+
+// WithRenderer draws the art with r instead of a renderer on the standard streams
+func (a *Art) WithRenderer(r *renderer.Renderer) *Art {
+	a.renderer = r
+	return a
+}
+
 func (a *Art) Render() {
-	r := renderer.New()
+	r := a.renderer
+	if r == nil {
+		r = renderer.New()
+	}
 	defer r.Close()
 	
+	color := a.color
+	if color == "" {
+		th := a.theme
+		if th == nil {
+			th = theme.Current()
+		}
+		color = th.Primary.Sequence()
+	}
+	
 	for _, line := range a.content {
-		r.WriteStyled(line, a.color)
+		r.WriteStyled(line, color)
 		r.NewLine()
 	}
 }
@@ -41,8 +69,8 @@ func Box(title string, width, height int) *Art {
 	}
 	art[height-1] = "└" + strings.Repeat("─", width-2) + "┘"
 	
-	if title != "" && len(title) < width-4 {
-		art[0] = art[0][:2] + " " + title + " " + art[0][len(title)+4:]
+	if titleWidth := textwidth.String(title); title != "" && titleWidth < width-4 {
+		art[0] = "┌─ " + title + " " + strings.Repeat("─", width-titleWidth-5) + "┐"
 	}
 	
 	return &Art{content: art}
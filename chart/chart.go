@@ -6,6 +6,7 @@ import (
 	"strings"
 	"github.com/vynazevedo/termx/renderer"
 	"github.com/vynazevedo/termx/theme"
+	"github.com/vynazevedo/termx/textwidth"
 )
 
 type Chart struct {
@@ -14,6 +15,8 @@ type Chart struct {
 	width  int
 	height int
 	style  string
+	renderer *renderer.Renderer
+	theme    *theme.Theme
 }
 
 func New(data []float64) *Chart {
@@ -41,8 +44,31 @@ func (c *Chart) WithStyle(style string) *Chart {
 	return c
 }
 
+// WithRenderer draws the chart with r instead of a renderer on the standard streams
+func (c *Chart) WithRenderer(r *renderer.Renderer) *Chart {
+	c.renderer = r
+	return c
+}
+
+// WithTheme draws the chart with t instead of theme.Current()
+func (c *Chart) WithTheme(t *theme.Theme) *Chart {
+	c.theme = t
+	return c
+}
+
+// currentTheme returns the theme the chart is drawn with
+func (c *Chart) currentTheme() *theme.Theme {
+	if c.theme != nil {
+		return c.theme
+	}
+	return theme.Current()
+}
+
 func (c *Chart) Render() {
-	r := renderer.New()
+	r := c.renderer
+	if r == nil {
+		r = renderer.New()
+	}
 	defer r.Close()
 	
 	switch c.style {
@@ -72,16 +98,16 @@ func (c *Chart) renderBar(r *renderer.Renderer) {
 		barWidth = 3
 	}
 	
-	th := theme.Current()
+	th := c.currentTheme()
 	
 	for row := c.height; row > 0; row-- {
 		threshold := (float64(row) / float64(c.height)) * max
 		
-		r.WriteStyled(fmt.Sprintf("%6.1f │", threshold), th.Muted.Foreground)
+		r.WriteStyled(fmt.Sprintf("%6.1f │", threshold), th.Muted.Sequence())
 		
 		for _, value := range c.data {
 			if value >= threshold {
-				r.WriteStyled(strings.Repeat("█", barWidth-1)+" ", th.Primary.Foreground)
+				r.WriteStyled(strings.Repeat("█", barWidth-1)+" ", th.Primary.Sequence())
 			} else {
 				r.Write(strings.Repeat(" ", barWidth))
 			}
@@ -89,14 +115,14 @@ func (c *Chart) renderBar(r *renderer.Renderer) {
 		r.NewLine()
 	}
 	
-	r.WriteStyled("       └"+strings.Repeat("─", len(c.data)*barWidth), th.Muted.Foreground)
+	r.WriteStyled("       └"+strings.Repeat("─", len(c.data)*barWidth), th.Muted.Sequence())
 	r.NewLine()
 	
 	if c.labels != nil {
 		r.Write("        ")
 		for i, label := range c.labels {
 			if i < len(c.data) {
-				r.Write(fmt.Sprintf("%-*s", barWidth, label))
+				r.Write(textwidth.PadRight(label, barWidth))
 			}
 		}
 		r.NewLine()
@@ -175,23 +201,23 @@ func (c *Chart) renderLine(r *renderer.Renderer) {
 		}
 	}
 	
-	th := theme.Current()
+	th := c.currentTheme()
 	
 	for i, row := range grid {
 		value := max - (float64(i)/float64(c.height-1))*(max-min)
-		r.WriteStyled(fmt.Sprintf("%6.1f │", value), th.Muted.Foreground)
+		r.WriteStyled(fmt.Sprintf("%6.1f │", value), th.Muted.Sequence())
 		
 		for _, cell := range row {
 			if cell == " " {
 				r.Write(cell)
 			} else {
-				r.WriteStyled(cell, th.Primary.Foreground)
+				r.WriteStyled(cell, th.Primary.Sequence())
 			}
 		}
 		r.NewLine()
 	}
 	
-	r.WriteStyled("       └"+strings.Repeat("─", c.width), th.Muted.Foreground)
+	r.WriteStyled("       └"+strings.Repeat("─", c.width), th.Muted.Sequence())
 	r.NewLine()
 }
 
@@ -224,15 +250,15 @@ func (c *Chart) renderScatter(r *renderer.Renderer) {
 		}
 	}
 	
-	th := theme.Current()
+	th := c.currentTheme()
 	
 	for row := 0; row < c.height; row++ {
 		value := max - (float64(row)/float64(c.height-1))*max
-		r.WriteStyled(fmt.Sprintf("%6.1f │", value), th.Muted.Foreground)
+		r.WriteStyled(fmt.Sprintf("%6.1f │", value), th.Muted.Sequence())
 		
 		for col := 0; col < c.width; col++ {
 			if grid[row][col] {
-				r.WriteStyled("●", th.Primary.Foreground)
+				r.WriteStyled("●", th.Primary.Sequence())
 			} else {
 				r.Write(" ")
 			}
@@ -240,6 +266,6 @@ func (c *Chart) renderScatter(r *renderer.Renderer) {
 		r.NewLine()
 	}
 	
-	r.WriteStyled("       └"+strings.Repeat("─", c.width), th.Muted.Foreground)
+	r.WriteStyled("       └"+strings.Repeat("─", c.width), th.Muted.Sequence())
 	r.NewLine()
 }
\ No newline at end of file
@@ -0,0 +1,45 @@
+package chart_test
+
+import (
+	"testing"
+
+	"github.com/vynazevedo/termx/chart"
+	"github.com/vynazevedo/termx/termxtest"
+	"github.com/vynazevedo/termx/theme"
+)
+
+func TestRenderGolden(t *testing.T) {
+	data := []float64{3, 7, 2, 9, 5}
+	tests := []struct {
+		name  string
+		chart *chart.Chart
+	}{
+		{"bar", chart.New(data).WithSize(20, 6).WithLabels([]string{"mon", "tue", "wed", "thu", "fri"})},
+		{"line", chart.New(data).WithSize(20, 6).WithStyle("line")},
+		{"scatter", chart.New(data).WithSize(20, 6).WithStyle("scatter")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term := termxtest.NewTerminal(40, 10)
+			tt.chart.WithTheme(theme.Default).WithRenderer(term.Renderer()).Render()
+			term.AssertGolden(t, "chart_"+tt.name)
+		})
+	}
+}
+
+// Bars are drawn in the primary style of the theme and the axis in the
+// muted one
+func TestRenderStyles(t *testing.T) {
+	term := termxtest.NewTerminal(40, 10)
+	chart.New([]float64{1}).WithSize(6, 2).WithTheme(theme.Dracula).WithRenderer(term.Renderer()).Render()
+
+	x, y, ok := term.Find("█")
+	if !ok {
+		t.Fatal("no bar on the screen")
+	}
+	bar := term.Cell(x, y).Style
+	axis := term.Cell(7, 0).Style
+	if bar.Fg == "" || axis.Fg == "" || bar.Fg == axis.Fg {
+		t.Errorf("bar color %q and axis color %q, want two different theme colors", bar.Fg, axis.Fg)
+	}
+}
@@ -0,0 +1,165 @@
+package colorprofile
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoBackground is returned by QueryBackground when the terminal does not
+// report its background color in time
+var ErrNoBackground = errors.New("colorprofile: terminal did not report its background color")
+
+// lateReplyTimeout is how long a query keeps reading after its timeout for
+// answers that arrive late, such as over a slow SSH connection
+const lateReplyTimeout = 250 * time.Millisecond
+
+// BackgroundTimeout is how long Background waits for the terminal to report
+// its background color
+var BackgroundTimeout = 100 * time.Millisecond
+
+// background caches the result of Background
+var background struct {
+	once     sync.Once
+	mu       sync.Mutex
+	dark, ok bool
+}
+
+// QueryBackground asks the terminal on in and out for its background color
+// with OSC 11 and waits up to timeout for the answer. It switches in to raw
+// mode while it waits, so it must not run while something else reads in,
+// such as a running component. After the timeout it keeps reading a little
+// longer for a late answer, so that it does not reach the next reader of in
+// as keys.
+//
+// The query is followed by a request for the device attributes, which every
+// terminal answers, so terminals that ignore OSC 11 do not cost the whole
+// timeout.
+func QueryBackground(in, out *os.File, timeout time.Duration) (r, g, b int, err error) {
+	reply, err := query(in, out, "\033]11;?\033\\\033[c", timeout)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	r, g, b, ok := parseOSC11(reply)
+	if !ok {
+		return 0, 0, 0, ErrNoBackground
+	}
+	return r, g, b, nil
+}
+
+// replyComplete reports whether buf holds the answer to the device
+// attributes request (ESC [ ? ... c), which terminals send after any answer
+// to the queries before it
+func replyComplete(buf []byte) bool {
+	i := bytes.Index(buf, []byte("\033[?"))
+	if i < 0 {
+		return false
+	}
+	for _, c := range buf[i+3:] {
+		switch {
+		case c == 'c':
+			return true
+		case (c < '0' || c > '9') && c != ';':
+			return false
+		}
+	}
+	return false
+}
+
+// parseOSC11 extracts the color from an OSC 11 answer such as
+// "ESC ] 11 ; rgb:ffff/ffff/ffff ESC \"
+func parseOSC11(buf []byte) (r, g, b int, ok bool) {
+	i := bytes.Index(buf, []byte("\033]11;"))
+	if i < 0 {
+		return 0, 0, 0, false
+	}
+	body := buf[i+5:]
+	end := bytes.IndexAny(body, "\a\033")
+	if end < 0 {
+		return 0, 0, 0, false
+	}
+
+	spec := string(body[:end])
+	switch {
+	case strings.HasPrefix(spec, "rgb:"):
+		spec = spec[4:]
+	case strings.HasPrefix(spec, "rgba:"):
+		spec = spec[5:]
+	default:
+		return 0, 0, 0, false
+	}
+	parts := strings.Split(spec, "/")
+	if len(parts) < 3 {
+		return 0, 0, 0, false
+	}
+
+	var rgb [3]int
+	for n := range rgb {
+		// Each channel has 1 to 4 hex digits, scaled to its own range
+		p := parts[n]
+		v, err := strconv.ParseUint(p, 16, 16)
+		if err != nil || len(p) == 0 || len(p) > 4 {
+			return 0, 0, 0, false
+		}
+		rgb[n] = int(v * 255 / (1<<(4*len(p)) - 1))
+	}
+	return rgb[0], rgb[1], rgb[2], true
+}
+
+// IsDark reports whether r, g, b is a dark color, by its relative luminance
+func IsDark(r, g, b int) bool {
+	return 2126*r+7152*g+722*b < 5000*255
+}
+
+// BackgroundFromEnv tells whether the background is dark from COLORFGBG,
+// which some terminals set to "fg;bg" with palette indices. ok is false when
+// the variable is missing or cannot be read.
+func BackgroundFromEnv(getenv func(string) string) (dark, ok bool) {
+	v := getenv("COLORFGBG")
+	if v == "" {
+		return false, false
+	}
+	fields := strings.Split(v, ";")
+	bg, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil || bg < 0 || bg > 15 {
+		return false, false
+	}
+	// 7 is light gray and 9 to 15 are the bright colors, except 8, dark gray
+	return bg < 7 || bg == 8, true
+}
+
+// DarkBackground reports whether the terminal on in and out has a dark
+// background, asking it with QueryBackground and falling back to
+// COLORFGBG. ok is false when neither tells.
+func DarkBackground(in, out *os.File, timeout time.Duration) (dark, ok bool) {
+	if r, g, b, err := QueryBackground(in, out, timeout); err == nil {
+		return IsDark(r, g, b), true
+	}
+	return BackgroundFromEnv(os.Getenv)
+}
+
+// Background reports whether the terminal on the standard streams has a
+// dark background, asking it with DarkBackground the first time only.
+// Renderers on the standard streams call it before they read keys, so that
+// the answer does not end up among them.
+func Background() (dark, ok bool) {
+	background.once.Do(func() {
+		dark, ok := DarkBackground(os.Stdin, os.Stdout, BackgroundTimeout)
+		background.mu.Lock()
+		background.dark, background.ok = dark, ok
+		background.mu.Unlock()
+	})
+	return CachedBackground()
+}
+
+// CachedBackground returns what Background found without asking the
+// terminal. ok is false until Background got an answer.
+func CachedBackground() (dark, ok bool) {
+	background.mu.Lock()
+	defer background.mu.Unlock()
+	return background.dark, background.ok
+}
@@ -0,0 +1,62 @@
+package colorprofile
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPTY returns the master and slave sides of a new pseudo terminal
+func openPTY(t *testing.T) (master, slave *os.File) {
+	t.Helper()
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		t.Skipf("no pseudo terminals: %v", err)
+	}
+	t.Cleanup(func() { master.Close() })
+
+	fd := int(master.Fd())
+	if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		t.Fatal(err)
+	}
+	n, err := unix.IoctlGetInt(fd, unix.TIOCGPTN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slave, err = os.OpenFile("/dev/pts/"+strconv.Itoa(n), os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { slave.Close() })
+	return master, slave
+}
+
+// An answer that arrives after the timeout must be read by the query
+// instead of being left in the input, where a component would take it for
+// keys
+func TestQueryBackgroundReadsLateAnswer(t *testing.T) {
+	master, slave := openPTY(t)
+
+	go func() {
+		buf := make([]byte, 64)
+		master.Read(buf)
+		time.Sleep(150 * time.Millisecond)
+		master.WriteString("\033]11;rgb:ffff/ffff/ffff\033\\\033[?62;c")
+	}()
+
+	r, g, b, err := QueryBackground(slave, slave, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("QueryBackground() error = %v", err)
+	}
+	if r != 255 || g != 255 || b != 255 {
+		t.Errorf("QueryBackground() = %d, %d, %d, want 255, 255, 255", r, g, b)
+	}
+
+	fds := []unix.PollFd{{Fd: int32(slave.Fd()), Events: unix.POLLIN}}
+	if n, _ := unix.Poll(fds, 100); n > 0 {
+		t.Error("the answer was left in the terminal input")
+	}
+}
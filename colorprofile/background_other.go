@@ -0,0 +1,14 @@
+//go:build !unix
+
+package colorprofile
+
+import (
+	"os"
+	"time"
+)
+
+// query is not supported without poll: a read that never gets an answer
+// could not be abandoned and would steal the next key from the program
+func query(in, out *os.File, seq string, timeout time.Duration) ([]byte, error) {
+	return nil, ErrNoBackground
+}
@@ -0,0 +1,88 @@
+package colorprofile
+
+import "testing"
+
+func TestParseOSC11(t *testing.T) {
+	tests := []struct {
+		in      string
+		r, g, b int
+		ok      bool
+	}{
+		{"\033]11;rgb:ffff/ffff/ffff\033\\", 255, 255, 255, true},
+		{"\033]11;rgb:0000/2b2b/3636\a", 0, 43, 54, true},
+		{"\033]11;rgb:f/8/0\033\\", 255, 136, 0, true},
+		{"\033]11;rgba:ff/00/00/ff\033\\\033[?62;c", 255, 0, 0, true},
+		{"noise\033]11;rgb:fdfd/f6f6/e3e3\033\\", 253, 246, 227, true},
+		{"\033]11;rgb:ffff/ffff\033\\", 0, 0, 0, false},
+		{"\033]11;#ffffff\033\\", 0, 0, 0, false},
+		{"\033]11;rgb:fffff/0/0\033\\", 0, 0, 0, false},
+		{"\033]11;rgb:ffff/ffff/ffff", 0, 0, 0, false},
+		{"\033[?62;c", 0, 0, 0, false},
+	}
+	for _, tt := range tests {
+		r, g, b, ok := parseOSC11([]byte(tt.in))
+		if r != tt.r || g != tt.g || b != tt.b || ok != tt.ok {
+			t.Errorf("parseOSC11(%q) = %d, %d, %d, %v, want %d, %d, %d, %v", tt.in, r, g, b, ok, tt.r, tt.g, tt.b, tt.ok)
+		}
+	}
+}
+
+func TestReplyComplete(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"", false},
+		{"\033]11;rgb:0/0/0\033\\", false},
+		{"\033]11;rgb:0/0/0\033\\\033[?62;22c", true},
+		{"\033[?1;2c", true},
+		{"\033[?62;2", false},
+		{"\033[?6x2c", false},
+	}
+	for _, tt := range tests {
+		if got := replyComplete([]byte(tt.in)); got != tt.want {
+			t.Errorf("replyComplete(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIsDark(t *testing.T) {
+	tests := []struct {
+		r, g, b int
+		want    bool
+	}{
+		{0, 0, 0, true},
+		{255, 255, 255, false},
+		{0, 43, 54, true},      // Solarized dark
+		{253, 246, 227, false}, // Solarized light
+		{40, 42, 54, true},     // Dracula
+	}
+	for _, tt := range tests {
+		if got := IsDark(tt.r, tt.g, tt.b); got != tt.want {
+			t.Errorf("IsDark(%d, %d, %d) = %v, want %v", tt.r, tt.g, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestBackgroundFromEnv(t *testing.T) {
+	tests := []struct {
+		colorfgbg string
+		dark, ok  bool
+	}{
+		{"15;0", true, true},
+		{"0;15", false, true},
+		{"0;7", false, true},
+		{"7;8", true, true},
+		{"default;default;0", true, true},
+		{"", false, false},
+		{"15;default", false, false},
+		{"15;16", false, false},
+	}
+	for _, tt := range tests {
+		getenv := func(string) string { return tt.colorfgbg }
+		dark, ok := BackgroundFromEnv(getenv)
+		if dark != tt.dark || ok != tt.ok {
+			t.Errorf("BackgroundFromEnv(COLORFGBG=%q) = %v, %v, want %v, %v", tt.colorfgbg, dark, ok, tt.dark, tt.ok)
+		}
+	}
+}
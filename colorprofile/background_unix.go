@@ -0,0 +1,73 @@
+//go:build unix
+
+package colorprofile
+
+import (
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+	"golang.org/x/term"
+)
+
+// query writes seq to out and collects what in receives until the device
+// attributes answer arrives or timeout passes. After a timeout it keeps
+// reading for lateReplyTimeout, so that answers still on their way are not
+// left in the input for the program to read as keys.
+func query(in, out *os.File, seq string, timeout time.Duration) ([]byte, error) {
+	fd := int(in.Fd())
+	if !term.IsTerminal(fd) || !term.IsTerminal(int(out.Fd())) {
+		return nil, errors.New("colorprofile: not a terminal")
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	defer term.Restore(fd, state)
+
+	if _, err := io.WriteString(out, seq); err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	late := false
+	var reply []byte
+	buf := make([]byte, 256)
+	for !replyComplete(reply) {
+		left := time.Until(deadline)
+		if left <= 0 && late {
+			return nil, ErrNoBackground
+		}
+		if left <= 0 {
+			late = true
+			deadline = time.Now().Add(lateReplyTimeout)
+			continue
+		}
+		fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+		n, err := unix.Poll(fds, int(left.Milliseconds())+1)
+		if err == unix.EINTR {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			continue
+		}
+
+		n, err = unix.Read(fd, buf)
+		if err == unix.EINTR || err == unix.EAGAIN {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			return nil, io.EOF
+		}
+		reply = append(reply, buf[:n]...)
+	}
+	return reply, nil
+}
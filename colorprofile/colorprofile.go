@@ -0,0 +1,305 @@
+// Package colorprofile detects how many colors a terminal supports and
+// converts SGR escape sequences down to what it can display.
+package colorprofile
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Profile is a level of color support, ordered from least to most capable
+type Profile int
+
+const (
+	// Ascii outputs no escape sequences at all, as for files and pipes
+	Ascii Profile = iota
+	// NoColor keeps text attributes such as bold but drops every color
+	NoColor
+	// ANSI supports the 16 basic colors
+	ANSI
+	// ANSI256 supports the xterm 256 color palette
+	ANSI256
+	// TrueColor supports 24-bit RGB colors
+	TrueColor
+)
+
+func (p Profile) String() string {
+	switch p {
+	case Ascii:
+		return "ascii"
+	case NoColor:
+		return "nocolor"
+	case ANSI:
+		return "ansi"
+	case ANSI256:
+		return "ansi256"
+	case TrueColor:
+		return "truecolor"
+	}
+	return "Profile(" + strconv.Itoa(int(p)) + ")"
+}
+
+// Detect returns the profile for output written to w, based on whether w is
+// a terminal and on the environment of the process.
+func Detect(w io.Writer) Profile {
+	tty := false
+	if f, ok := w.(interface{ Fd() uintptr }); ok {
+		tty = term.IsTerminal(int(f.Fd()))
+	}
+	return FromEnv(os.Getenv, tty)
+}
+
+// FromEnv returns the profile described by the environment variables that
+// getenv reports. tty tells whether the output is a terminal; colors are
+// only used on a terminal unless CLICOLOR_FORCE is set.
+//
+// NO_COLOR disables colors and takes precedence over everything else.
+// TERM=dumb disables escape sequences. COLORTERM=truecolor or 24bit selects
+// TrueColor and a TERM ending in 256color selects ANSI256.
+func FromEnv(getenv func(string) string, tty bool) Profile {
+	force := getenv("CLICOLOR_FORCE")
+	forced := force != "" && force != "0"
+
+	if !tty && !forced {
+		return Ascii
+	}
+	if getenv("NO_COLOR") != "" {
+		if tty {
+			return NoColor
+		}
+		return Ascii
+	}
+
+	termName := strings.ToLower(getenv("TERM"))
+	if termName == "dumb" && !forced {
+		return Ascii
+	}
+
+	switch strings.ToLower(getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return TrueColor
+	}
+
+	switch {
+	case strings.Contains(termName, "truecolor"), strings.Contains(termName, "direct"):
+		return TrueColor
+	case strings.Contains(termName, "256color"):
+		return ANSI256
+	case getenv("WT_SESSION") != "":
+		// Windows Terminal does not set TERM or COLORTERM
+		return TrueColor
+	}
+	return ANSI
+}
+
+// Convert rewrites a single SGR sequence (ESC [ ... m) so that it only uses
+// colors the profile supports. Colors are mapped to the nearest available
+// one, or removed under NoColor. The result is empty when nothing is left
+// to send.
+func (p Profile) Convert(seq string) string {
+	if p >= TrueColor {
+		return seq
+	}
+	if p == Ascii {
+		return ""
+	}
+	if !strings.HasPrefix(seq, "\033[") || !strings.HasSuffix(seq, "m") {
+		return seq
+	}
+
+	body := seq[2 : len(seq)-1]
+	if body == "" {
+		return seq
+	}
+
+	params := strings.Split(body, ";")
+	var out []string
+	for i := 0; i < len(params); i++ {
+		n, err := strconv.Atoi(params[i])
+		if err != nil {
+			// Colon sub-parameters or other extensions: only keep them
+			// when colors are not involved
+			if p != NoColor && !strings.HasPrefix(params[i], "38:") && !strings.HasPrefix(params[i], "48:") {
+				out = append(out, params[i])
+			}
+			continue
+		}
+
+		switch {
+		case n == 38 || n == 48:
+			c, used, ok := extendedColor(params[i+1:])
+			i += used
+			if !ok {
+				continue
+			}
+			if code := p.colorCode(c, n == 48); code != "" {
+				out = append(out, code)
+			}
+		case (n >= 30 && n <= 37) || (n >= 40 && n <= 47) || (n >= 90 && n <= 97) || (n >= 100 && n <= 107) || n == 39 || n == 49:
+			if p != NoColor {
+				out = append(out, params[i])
+			}
+		default:
+			out = append(out, params[i])
+		}
+	}
+
+	if len(out) == 0 {
+		return ""
+	}
+	return "\033[" + strings.Join(out, ";") + "m"
+}
+
+// color is a color of an SGR sequence: either a palette index or RGB
+type color struct {
+	index   int
+	r, g, b int
+	rgb     bool
+}
+
+// extendedColor parses the arguments following 38 or 48: 5;n for a palette
+// index or 2;r;g;b for RGB. It returns the number of parameters consumed.
+func extendedColor(params []string) (color, int, bool) {
+	if len(params) == 0 {
+		return color{}, 0, false
+	}
+	switch params[0] {
+	case "5":
+		if len(params) < 2 {
+			return color{}, len(params), false
+		}
+		n, err := strconv.Atoi(params[1])
+		if err != nil || n < 0 || n > 255 {
+			return color{}, 2, false
+		}
+		return color{index: n}, 2, true
+	case "2":
+		if len(params) < 4 {
+			return color{}, len(params), false
+		}
+		var rgb [3]int
+		for i := range rgb {
+			n, err := strconv.Atoi(params[1+i])
+			if err != nil || n < 0 || n > 255 {
+				return color{}, 4, false
+			}
+			rgb[i] = n
+		}
+		return color{r: rgb[0], g: rgb[1], b: rgb[2], rgb: true}, 4, true
+	}
+	return color{}, 1, false
+}
+
+// colorCode returns the SGR parameters selecting c in the profile
+func (p Profile) colorCode(c color, background bool) string {
+	prefix, base, bright := "38", 30, 90
+	if background {
+		prefix, base, bright = "48", 40, 100
+	}
+
+	switch p {
+	case NoColor:
+		return ""
+	case ANSI256:
+		if c.rgb {
+			c = color{index: RGBTo256(c.r, c.g, c.b)}
+		}
+		return prefix + ";5;" + strconv.Itoa(c.index)
+	}
+
+	index := c.index
+	if c.rgb {
+		index = RGBTo16(c.r, c.g, c.b)
+	} else if index >= 16 {
+		r, g, b := PaletteRGB(index)
+		index = RGBTo16(r, g, b)
+	}
+	if index < 8 {
+		return strconv.Itoa(base + index)
+	}
+	return strconv.Itoa(bright + index - 8)
+}
+
+// ansiPalette holds the usual RGB values of the 16 basic colors
+var ansiPalette = [16][3]int{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels are the channel values of the 6x6x6 color cube of the 256 palette
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+// PaletteRGB returns the RGB value of an index of the 256 color palette
+func PaletteRGB(index int) (r, g, b int) {
+	switch {
+	case index < 16:
+		c := ansiPalette[index]
+		return c[0], c[1], c[2]
+	case index < 232:
+		index -= 16
+		return cubeLevels[index/36], cubeLevels[index/6%6], cubeLevels[index%6]
+	}
+	v := 8 + (index-232)*10
+	return v, v, v
+}
+
+// RGBTo256 returns the index of the 256 color palette closest to r, g, b
+func RGBTo256(r, g, b int) int {
+	cube := func(v int) int {
+		best := 0
+		for i, level := range cubeLevels {
+			if abs(v-level) < abs(v-cubeLevels[best]) {
+				best = i
+			}
+		}
+		return best
+	}
+	ci, cj, ck := cube(r), cube(g), cube(b)
+	cubeIndex := 16 + 36*ci + 6*cj + ck
+
+	avg := (r + g + b) / 3
+	gray := (avg - 8 + 5) / 10
+	if gray < 0 {
+		gray = 0
+	} else if gray > 23 {
+		gray = 23
+	}
+	grayIndex := 232 + gray
+
+	if distance(r, g, b, grayIndex) < distance(r, g, b, cubeIndex) {
+		return grayIndex
+	}
+	return cubeIndex
+}
+
+// RGBTo16 returns the index of the basic color closest to r, g, b
+func RGBTo16(r, g, b int) int {
+	best := 0
+	for i := 1; i < len(ansiPalette); i++ {
+		if distance(r, g, b, i) < distance(r, g, b, best) {
+			best = i
+		}
+	}
+	return best
+}
+
+// distance is the squared distance between r, g, b and a palette color,
+// weighted roughly by how sensitive the eye is to each channel
+func distance(r, g, b, index int) int {
+	pr, pg, pb := PaletteRGB(index)
+	dr, dg, db := r-pr, g-pg, b-pb
+	return 3*dr*dr + 4*dg*dg + 2*db*db
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
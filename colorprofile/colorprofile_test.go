@@ -0,0 +1,75 @@
+package colorprofile_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/vynazevedo/termx/colorprofile"
+)
+
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		tty  bool
+		want colorprofile.Profile
+	}{
+		{"pipe", map[string]string{"TERM": "xterm-256color"}, false, colorprofile.Ascii},
+		{"forced pipe", map[string]string{"TERM": "xterm-256color", "CLICOLOR_FORCE": "1"}, false, colorprofile.ANSI256},
+		{"force disabled", map[string]string{"COLORTERM": "truecolor", "CLICOLOR_FORCE": "0"}, false, colorprofile.Ascii},
+		{"no color", map[string]string{"NO_COLOR": "1", "COLORTERM": "truecolor"}, true, colorprofile.NoColor},
+		{"no color forced pipe", map[string]string{"NO_COLOR": "1", "CLICOLOR_FORCE": "1"}, false, colorprofile.Ascii},
+		{"dumb", map[string]string{"TERM": "dumb"}, true, colorprofile.Ascii},
+		{"dumb forced", map[string]string{"TERM": "dumb", "CLICOLOR_FORCE": "1"}, true, colorprofile.ANSI},
+		{"colorterm", map[string]string{"TERM": "xterm", "COLORTERM": "24bit"}, true, colorprofile.TrueColor},
+		{"direct", map[string]string{"TERM": "xterm-direct"}, true, colorprofile.TrueColor},
+		{"256color", map[string]string{"TERM": "screen-256color"}, true, colorprofile.ANSI256},
+		{"windows terminal", map[string]string{"WT_SESSION": "abc"}, true, colorprofile.TrueColor},
+		{"xterm", map[string]string{"TERM": "xterm"}, true, colorprofile.ANSI},
+	}
+	for _, tt := range tests {
+		getenv := func(key string) string { return tt.env[key] }
+		if got := colorprofile.FromEnv(getenv, tt.tty); got != tt.want {
+			t.Errorf("%s: FromEnv() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDetectNonTerminal(t *testing.T) {
+	t.Setenv("CLICOLOR_FORCE", "")
+	if got := colorprofile.Detect(&bytes.Buffer{}); got != colorprofile.Ascii {
+		t.Errorf("Detect(buffer) = %v, want %v", got, colorprofile.Ascii)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		profile colorprofile.Profile
+		in      string
+		want    string
+	}{
+		{colorprofile.TrueColor, "\033[38;2;1;2;3m", "\033[38;2;1;2;3m"},
+		{colorprofile.Ascii, "\033[1;31m", ""},
+		{colorprofile.ANSI256, "\033[38;2;255;0;0m", "\033[38;5;196m"},
+		{colorprofile.ANSI256, "\033[48;2;128;128;128m", "\033[48;5;244m"},
+		{colorprofile.ANSI256, "\033[1;38;5;202;4m", "\033[1;38;5;202;4m"},
+		{colorprofile.ANSI, "\033[38;2;255;0;0m", "\033[91m"},
+		{colorprofile.ANSI, "\033[48;5;4m", "\033[44m"},
+		{colorprofile.ANSI, "\033[1;38;5;15m", "\033[1;97m"},
+		{colorprofile.ANSI, "\033[31;42m", "\033[31;42m"},
+		{colorprofile.NoColor, "\033[1;31;48;5;202;4m", "\033[1;4m"},
+		{colorprofile.NoColor, "\033[31m", ""},
+		{colorprofile.ANSI, "\033[0m", "\033[0m"},
+		{colorprofile.ANSI, "\033[m", "\033[m"},
+		{colorprofile.ANSI, "\033[38;5m", ""},
+		{colorprofile.ANSI, "\033[38;2;300;0;0;1m", "\033[1m"},
+		{colorprofile.ANSI, "\033[4:3m", "\033[4:3m"},
+		{colorprofile.NoColor, "\033[4:3m", ""},
+		{colorprofile.ANSI, "\033[2J", "\033[2J"},
+	}
+	for _, tt := range tests {
+		if got := tt.profile.Convert(tt.in); got != tt.want {
+			t.Errorf("%v.Convert(%q) = %q, want %q", tt.profile, tt.in, got, tt.want)
+		}
+	}
+}
@@ -1,11 +1,15 @@
 package combobox
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
+	"github.com/vynazevedo/termx/help"
+	"github.com/vynazevedo/termx/keymap"
 	"github.com/vynazevedo/termx/renderer"
 	"github.com/vynazevedo/termx/theme"
+	"github.com/vynazevedo/termx/widget"
 )
 
 // ComboBox represents a searchable select component with custom input
@@ -24,6 +28,18 @@ type ComboBox struct {
 	renderer     *renderer.Renderer
 	maxDisplay   int
 	caseSensitive bool
+	message      string // validation error shown until the next key
+	keymap       *keymap.Keymap
+}
+
+// actions are the keymap actions the combobox responds to
+var actions = []keymap.Action{
+	keymap.CursorUp,
+	keymap.CursorDown,
+	keymap.Complete,
+	keymap.Accept,
+	keymap.DeleteBackward,
+	keymap.Cancel,
 }
 
 // New creates a new ComboBox instance
@@ -37,7 +53,6 @@ func New(label string, options []string, result *string) *ComboBox {
 		filtered:     make([]string, len(options)),
 		showDropdown: false,
 		allowCustom:  true,
-		theme:        theme.Default(),
 		renderer:     renderer.New(),
 		maxDisplay:   8,
 		caseSensitive: false,
@@ -56,12 +71,40 @@ func (cb *ComboBox) WithValidator(validator func(string) error) *ComboBox {
 	return cb
 }
 
-// WithTheme sets the theme
+// WithTheme draws the combobox with t instead of theme.Current()
 func (cb *ComboBox) WithTheme(t *theme.Theme) *ComboBox {
 	cb.theme = t
 	return cb
 }
 
+// currentTheme returns the theme the combobox is drawn with
+func (cb *ComboBox) currentTheme() *theme.Theme {
+	if cb.theme != nil {
+		return cb.theme
+	}
+	return theme.Current()
+}
+
+// WithRenderer sets the renderer used to draw the combobox
+func (cb *ComboBox) WithRenderer(r *renderer.Renderer) *ComboBox {
+	cb.renderer = r
+	return cb
+}
+
+// WithKeymap resolves keys through k instead of keymap.Current()
+func (cb *ComboBox) WithKeymap(k *keymap.Keymap) *ComboBox {
+	cb.keymap = k
+	return cb
+}
+
+// keys returns the keymap the combobox resolves keys through
+func (cb *ComboBox) keys() *keymap.Keymap {
+	if cb.keymap != nil {
+		return cb.keymap
+	}
+	return keymap.Current()
+}
+
 // WithMaxDisplay sets maximum number of options to display
 func (cb *ComboBox) WithMaxDisplay(max int) *ComboBox {
 	cb.maxDisplay = max
@@ -143,33 +186,37 @@ func (cb *ComboBox) filterOptions() {
 	cb.filtered = uniqueFiltered
 }
 
-// render displays the combobox interface
-func (cb *ComboBox) render() {
-	cb.renderer.ClearScreen()
+// Draw draws the label, the input field and the dropdown from the top left
+// corner of area
+func (cb *ComboBox) Draw(c *renderer.Canvas, area renderer.Rect) {
+	th := cb.currentTheme()
+	y := area.Y
+	line := func(text string) {
+		c.Print(area.X, y, text)
+		y++
+	}
 	
 	// Label
-	fmt.Printf("%s%s%s\n", cb.theme.Primary, cb.label, cb.theme.Reset)
+	line(th.Primary.Sprint(cb.label))
 	
 	// Help text
-	helpText := "Digite para buscar, ↑↓ para navegar, Enter para selecionar, Esc para cancelar"
-	if cb.allowCustom {
-		helpText = "Digite valor customizado ou busque, ↑↓ para navegar, Enter para confirmar"
-	}
-	fmt.Printf("%s%s%s\n", cb.theme.Muted, helpText, cb.theme.Reset)
+	line(help.Bar(cb.KeyBindings(), area.Width, th))
 	
 	// Input field
 	displayValue := cb.value
 	if displayValue == "" && cb.placeholder != "" {
-		displayValue = cb.theme.Muted + cb.placeholder + cb.theme.Reset
+		displayValue = th.Placeholder.Sprint(cb.placeholder)
 	} else {
-		displayValue = cb.theme.Input + displayValue + cb.theme.Reset
+		displayValue = th.Text.Sprint(displayValue)
 	}
 	
-	fmt.Printf("\n%s> %s%s\n", cb.theme.Primary, displayValue, cb.theme.Reset)
+	y++
+	line(th.Primary.Sprint("> ") + displayValue)
 	
 	// Dropdown
 	if cb.showDropdown && len(cb.filtered) > 0 {
-		fmt.Printf("\n%sOpções disponíveis:%s\n", cb.theme.Secondary, cb.theme.Reset)
+		y++
+		line(th.Secondary.Sprint("Available options:"))
 		
 		displayCount := cb.maxDisplay
 		if len(cb.filtered) < displayCount {
@@ -180,24 +227,25 @@ func (cb *ComboBox) render() {
 			option := cb.filtered[i]
 			cursor := "  "
 			
+			base := theme.Style{}
 			if i == cb.cursor {
-				cursor = cb.theme.Primary + "❯ " + cb.theme.Reset
-				option = cb.theme.Highlight + option + cb.theme.Reset
+				cursor = th.Cursor.Sprint("❯ ")
+				base = th.Selected
 			}
 			
-			fmt.Printf("%s%s\n", cursor, option)
+			line(cursor + theme.Highlight(option, cb.value, base, th.Match))
 		}
 		
 		if len(cb.filtered) > displayCount {
 			remaining := len(cb.filtered) - displayCount
-			fmt.Printf("%s... e mais %d opções%s\n", cb.theme.Muted, remaining, cb.theme.Reset)
+			line(th.Muted.Sprintf("... and %d more options", remaining))
 		}
 	} else if cb.showDropdown && len(cb.filtered) == 0 && cb.value != "" {
+		y++
 		if cb.allowCustom {
-			fmt.Printf("\n%sNenhuma opção encontrada. Valor customizado será usado.%s\n", 
-				cb.theme.Warning, cb.theme.Reset)
+			line(th.Warning.Sprint("No options found. The custom value will be used."))
 		} else {
-			fmt.Printf("\n%sNenhuma opção encontrada.%s\n", cb.theme.Error, cb.theme.Reset)
+			line(th.Error.Sprint("No options found."))
 		}
 	}
 	
@@ -213,10 +261,36 @@ func (cb *ComboBox) render() {
 		}
 		
 		if !isExistingOption {
-			fmt.Printf("\n%s💡 Valor customizado: \"%s\"%s\n", 
-				cb.theme.Info, cb.value, cb.theme.Reset)
+			y++
+			line(th.Info.Sprintf("💡 Valor customizado: \"%s\"", cb.value))
 		}
 	}
+	
+	// Validation error, shown until the next key
+	if cb.message != "" {
+		y++
+		line(th.Error.Sprint(cb.message))
+		line("Press any key to continue...")
+	}
+}
+
+// KeyBindings lists the keys of the combobox, for help
+func (cb *ComboBox) KeyBindings() []help.Binding {
+	k := cb.keys()
+	bindings := []help.Binding{
+		help.Bind(k, keymap.CursorUp, "previous option").InBar(),
+		help.Bind(k, keymap.CursorDown, "next option").InBar(),
+		help.Bind(k, keymap.Complete, "complete").InBar(),
+		help.Bind(k, keymap.Accept, "confirm").InBar(),
+		help.Bind(k, keymap.Cancel, "close list or cancel").InBar(),
+		help.Bind(k, keymap.DeleteBackward, "delete character"),
+		help.Interrupt,
+		help.Bind(k, keymap.Help, "help").InBar(),
+	}
+	for n := range bindings {
+		bindings[n] = bindings[n].TextEntry()
+	}
+	return bindings
 }
 
 // validateInput validates the current input
@@ -233,7 +307,7 @@ func (cb *ComboBox) validateInput() error {
 		}
 		
 		if !found {
-			return fmt.Errorf("valor deve ser selecionado da lista de opções")
+			return fmt.Errorf("value must be selected from the list of options")
 		}
 	}
 	
@@ -246,82 +320,101 @@ func (cb *ComboBox) validateInput() error {
 
 // Run executes the combobox interaction
 func (cb *ComboBox) Run() error {
-	defer cb.renderer.Close()
-	
+	return cb.RunContext(context.Background())
+}
+
+// RunContext is like Run but stops waiting for the user and returns
+// ctx.Err() once ctx is done
+func (cb *ComboBox) RunContext(ctx context.Context) error {
 	cb.filterOptions()
+	cb.message = ""
+	
+	return widget.Run(ctx, cb.renderer, help.WithOverlay(cb).WithTheme(cb.theme))
+}
+
+// Focusable reports that the combobox takes keyboard input
+func (cb *ComboBox) Focusable() bool {
+	return true
+}
+
+// HandleEvent edits the value and moves through the dropdown. It finishes on
+// Enter once the value is valid, storing it in the result, or on Esc with an
+// empty value.
+func (cb *ComboBox) HandleEvent(event *renderer.InputEvent) widget.Result {
+	if cb.message != "" {
+		// Any key dismisses the validation error
+		cb.message = ""
+		return widget.Handled
+	}
 	
-	for {
-		cb.render()
+	switch cb.keys().Lookup(event, true, actions...) {
+	case keymap.CursorUp:
+		if cb.showDropdown && cb.cursor > 0 {
+			cb.cursor--
+		} else if !cb.showDropdown {
+			cb.showDropdown = true
+			cb.cursor = 0
+		}
 		
-		key, err := cb.renderer.ReadKey()
-		if err != nil {
-			return err
+	case keymap.CursorDown:
+		if cb.showDropdown && cb.cursor < len(cb.filtered)-1 {
+			cb.cursor++
+		} else if !cb.showDropdown {
+			cb.showDropdown = true
+			cb.cursor = 0
 		}
 		
-		switch key {
-		case renderer.KeyUp:
-			if cb.showDropdown && cb.cursor > 0 {
-				cb.cursor--
-			} else if !cb.showDropdown {
-				cb.showDropdown = true
-				cb.cursor = 0
-			}
-			
-		case renderer.KeyDown:
-			if cb.showDropdown && cb.cursor < len(cb.filtered)-1 {
-				cb.cursor++
-			} else if !cb.showDropdown {
-				cb.showDropdown = true
-				cb.cursor = 0
-			}
-			
-		case renderer.KeyTab:
-			if len(cb.filtered) > 0 && cb.cursor < len(cb.filtered) {
-				cb.value = cb.filtered[cb.cursor]
-				cb.showDropdown = false
-				cb.filterOptions()
-			}
-			
-		case renderer.KeyEnter:
-			if cb.showDropdown && len(cb.filtered) > 0 && cb.cursor < len(cb.filtered) {
-				cb.value = cb.filtered[cb.cursor]
-				cb.showDropdown = false
-			}
-			
-			if err := cb.validateInput(); err != nil {
-				fmt.Printf("\n%s%s%s\n", cb.theme.Error, err.Error(), cb.theme.Reset)
-				fmt.Println("Pressione qualquer tecla para continuar...")
-				cb.renderer.ReadKey()
-				continue
-			}
-			
-			*cb.result = cb.value
-			return nil
-			
-		case renderer.KeyBackspace:
-			if len(cb.value) > 0 {
-				cb.value = cb.value[:len(cb.value)-1]
-				cb.filterOptions()
-				cb.cursor = 0
-				cb.showDropdown = len(cb.filtered) > 0
-			}
-			
-		case renderer.KeyEscape:
+	case keymap.Complete:
+		if len(cb.filtered) > 0 && cb.cursor < len(cb.filtered) {
+			cb.value = cb.filtered[cb.cursor]
 			cb.showDropdown = false
-			if cb.value == "" {
-				return fmt.Errorf("operação cancelada")
-			}
-			
-		default:
+			cb.filterOptions()
+		}
+		
+	case keymap.Accept:
+		if cb.showDropdown && len(cb.filtered) > 0 && cb.cursor < len(cb.filtered) {
+			cb.value = cb.filtered[cb.cursor]
+			cb.showDropdown = false
+		}
+		
+		if err := cb.validateInput(); err != nil {
+			cb.message = err.Error()
+			return widget.Handled
+		}
+		
+		*cb.result = cb.value
+		return widget.Done(nil)
+		
+	case keymap.DeleteBackward:
+		if len(cb.value) > 0 {
+			value := []rune(cb.value)
+			cb.value = string(value[:len(value)-1])
+			cb.filterOptions()
+			cb.cursor = 0
+			cb.showDropdown = len(cb.filtered) > 0
+		}
+		
+	case keymap.Cancel:
+		cb.showDropdown = false
+		if cb.value == "" {
+			return widget.Done(widget.ErrCancelled)
+		}
+		
+	default:
+		switch {
+		case event.Key == renderer.KeyPaste:
+			cb.value += renderer.SingleLine(event.Text)
+		case event.Printable():
 			// Handle regular character input
-			if len(key) == 1 && key[0] >= 32 && key[0] <= 126 {
-				cb.value += key
-				cb.filterOptions()
-				cb.cursor = 0
-				cb.showDropdown = true
-			}
+			cb.value += string(event.Rune)
+		default:
+			return widget.Ignored
 		}
+		cb.filterOptions()
+		cb.cursor = 0
+		cb.showDropdown = true
 	}
+	return widget.Handled
 }
 
 // Predefined combobox configurations
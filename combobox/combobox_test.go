@@ -0,0 +1,139 @@
+package combobox_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/vynazevedo/termx/combobox"
+	"github.com/vynazevedo/termx/termxtest"
+	"github.com/vynazevedo/termx/widget"
+)
+
+var langs = []string{"Java", "JavaScript", "Go", "Rust", "TypeScript", "Python"}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		strict bool
+		cased  bool
+		script func(term *termxtest.Terminal)
+		want   string
+	}{
+		{"first option", false, false, func(term *termxtest.Terminal) {
+			term.Press(termxtest.KeyDown, termxtest.KeyEnter)
+		}, "Java"},
+		{"exact match first", false, false, func(term *termxtest.Terminal) {
+			term.Type("java")
+			term.Press(termxtest.KeyEnter)
+		}, "Java"},
+		{"prefix after exact", false, false, func(term *termxtest.Terminal) {
+			term.Type("java")
+			term.Press(termxtest.KeyDown, termxtest.KeyEnter)
+		}, "JavaScript"},
+		{"contains in option order", false, false, func(term *termxtest.Terminal) {
+			term.Type("script")
+			term.Press(termxtest.KeyDown, termxtest.KeyEnter)
+		}, "TypeScript"},
+		{"complete", false, false, func(term *termxtest.Terminal) {
+			term.Type("py")
+			term.Press(termxtest.KeyTab, termxtest.KeyEnter)
+		}, "Python"},
+		{"backspace", false, false, func(term *termxtest.Terminal) {
+			term.Type("rux")
+			term.Press(termxtest.KeyBackspace)
+			term.Type("st")
+			term.Press(termxtest.KeyEnter)
+		}, "Rust"},
+		{"custom value", false, false, func(term *termxtest.Terminal) {
+			term.Type("zig")
+			term.Press(termxtest.KeyEnter)
+		}, "zig"},
+		{"case sensitive", false, true, func(term *termxtest.Terminal) {
+			term.Type("go")
+			term.Press(termxtest.KeyEnter)
+		}, "go"},
+		{"paste", false, false, func(term *termxtest.Terminal) {
+			term.Paste("Rust")
+			term.Press(termxtest.KeyEnter)
+		}, "Rust"},
+		{"strict rejects custom value", true, false, func(term *termxtest.Terminal) {
+			term.Type("zig")
+			term.Press(termxtest.KeyEnter, "x")
+			term.Press(termxtest.KeyBackspace, termxtest.KeyBackspace, termxtest.KeyBackspace)
+			term.Type("go")
+			term.Press(termxtest.KeyEnter)
+		}, "Go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term := termxtest.NewTerminal(60, 20)
+			tt.script(term)
+
+			var result string
+			cb := combobox.New("Language", langs, &result).WithRenderer(term.Renderer())
+			if tt.strict {
+				cb.WithoutCustomInput()
+			}
+			if tt.cased {
+				cb.WithCaseSensitiveSearch()
+			}
+			if err := cb.Run(); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if result != tt.want {
+				t.Errorf("result = %q, want %q", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrictShowsError(t *testing.T) {
+	term := termxtest.NewTerminal(60, 20)
+	term.Type("zig")
+	term.Press(termxtest.KeyEnter)
+
+	var result string
+	err := combobox.New("Language", langs, &result).WithoutCustomInput().WithRenderer(term.Renderer()).Run()
+	if err != io.EOF {
+		t.Fatalf("Run() error = %v, want %v once the keys run out", err, io.EOF)
+	}
+	term.AssertContains(t, "value must be selected from the list of options")
+	if result != "" {
+		t.Errorf("result = %q, want it unchanged", result)
+	}
+}
+
+func TestValidator(t *testing.T) {
+	term := termxtest.NewTerminal(60, 20)
+	term.Type("Go")
+	term.Press(termxtest.KeyEnter, "x", termxtest.KeyBackspace, termxtest.KeyBackspace)
+	term.Type("Rust")
+	term.Press(termxtest.KeyEnter)
+
+	notGo := func(s string) error {
+		if s == "Go" {
+			return errors.New("pick another one")
+		}
+		return nil
+	}
+	var result string
+	if err := combobox.New("Language", langs, &result).WithValidator(notGo).WithRenderer(term.Renderer()).Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result != "Rust" {
+		t.Errorf("result = %q, want %q", result, "Rust")
+	}
+}
+
+// Esc closes the dropdown, and cancels once the value is empty
+func TestCancel(t *testing.T) {
+	term := termxtest.NewTerminal(60, 20)
+	term.Type("g")
+	term.Press(termxtest.KeyEscape, termxtest.KeyBackspace, termxtest.KeyEscape)
+
+	var result string
+	if err := combobox.New("Language", langs, &result).WithRenderer(term.Renderer()).Run(); err != widget.ErrCancelled {
+		t.Fatalf("Run() error = %v, want %v", err, widget.ErrCancelled)
+	}
+}
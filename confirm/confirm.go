@@ -1,11 +1,15 @@
 package confirm
 
 import (
+	"context"
 	"errors"
 	"strings"
 
+	"github.com/vynazevedo/termx/help"
+	"github.com/vynazevedo/termx/keymap"
 	"github.com/vynazevedo/termx/renderer"
 	"github.com/vynazevedo/termx/theme"
+	"github.com/vynazevedo/termx/widget"
 )
 
 type Confirm struct {
@@ -15,6 +19,19 @@ type Confirm struct {
 	
 	selected bool
 	renderer *renderer.Renderer
+	inline   bool
+	keymap   *keymap.Keymap
+	theme    *theme.Theme
+}
+
+// actions are the keymap actions the prompt responds to
+var actions = []keymap.Action{
+	keymap.Accept,
+	keymap.Yes,
+	keymap.No,
+	keymap.Toggle,
+	keymap.CursorLeft,
+	keymap.CursorRight,
 }
 
 func New(label string, result *bool) *Confirm {
@@ -32,77 +49,207 @@ func (c *Confirm) WithDefault(defaultValue bool) *Confirm {
 	return c
 }
 
+// WithRenderer draws the prompt with r instead of a renderer on the standard streams
+func (c *Confirm) WithRenderer(r *renderer.Renderer) *Confirm {
+	c.renderer = r
+	return c
+}
+
+// WithKeymap resolves keys through k instead of keymap.Current()
+func (c *Confirm) WithKeymap(k *keymap.Keymap) *Confirm {
+	c.keymap = k
+	return c
+}
+
+// keys returns the keymap the prompt resolves keys through
+func (c *Confirm) keys() *keymap.Keymap {
+	if c.keymap != nil {
+		return c.keymap
+	}
+	return keymap.Current()
+}
+
+// WithTheme draws the prompt with t instead of theme.Current()
+func (c *Confirm) WithTheme(t *theme.Theme) *Confirm {
+	c.theme = t
+	return c
+}
+
+// currentTheme returns the theme the prompt is drawn with
+func (c *Confirm) currentTheme() *theme.Theme {
+	if c.theme != nil {
+		return c.theme
+	}
+	return theme.Current()
+}
+
+// Inline renders the prompt below the previous output at the cursor
+// position instead of taking over the screen. Once answered it collapses
+// to a one-line summary.
+func (c *Confirm) Inline() *Confirm {
+	c.inline = true
+	return c
+}
+
 func (c *Confirm) Run() error {
-	c.renderer = renderer.New()
-	if err := c.renderer.Init(); err != nil {
-		return err
+	return c.RunContext(context.Background())
+}
+
+// RunContext is like Run but stops waiting for the user and returns
+// ctx.Err() once ctx is done
+func (c *Confirm) RunContext(ctx context.Context) error {
+	if c.renderer == nil {
+		c.renderer = renderer.New()
+	}
+
+	var err error
+	if c.inline {
+		err = widget.RunInline(ctx, c.renderer, c)
+	} else {
+		err = widget.Run(ctx, c.renderer, help.WithOverlay(c).WithTheme(c.theme))
+	}
+	if errors.Is(err, renderer.ErrNotTerminal) {
+		return c.runLine(ctx)
+	}
+	if err == nil && c.inline {
+		th := c.currentTheme()
+		c.renderer.ClearInline()
+		c.renderer.Write(th.Success.Sprint("?") + " " + theme.Bold(c.Label) + ": " + th.Primary.Sprint(c.answer()) + "\n")
+	}
+	return err
+}
+
+// Focusable reports that the prompt takes keyboard input
+func (c *Confirm) Focusable() bool {
+	return true
+}
+
+// HandleEvent toggles the choice and finishes on Enter or a Y/N shortcut
+func (c *Confirm) HandleEvent(event *renderer.InputEvent) widget.Result {
+	if event.Key == renderer.KeyCtrlC {
+		return widget.Done(widget.ErrCancelled)
 	}
-	defer c.renderer.Restore()
+	
+	switch c.keys().Lookup(event, false, actions...) {
+	case keymap.Accept:
+		return c.accept()
+	
+	case keymap.Yes:
+		c.selected = true
+		return c.accept()
+	
+	case keymap.No:
+		c.selected = false
+		return c.accept()
+	
+	case keymap.Toggle, keymap.CursorLeft, keymap.CursorRight:
+		c.selected = !c.selected
+	
+	default:
+		return widget.Ignored
+	}
+	return widget.Handled
+}
+
+// KeyBindings lists the keys of the prompt, for help
+func (c *Confirm) KeyBindings() []help.Binding {
+	k := c.keys()
+	return []help.Binding{
+		help.Bind(k, keymap.Toggle, "toggle").InBar(),
+		help.Bind(k, keymap.CursorLeft, "toggle"),
+		help.Bind(k, keymap.CursorRight, "toggle"),
+		help.Bind(k, keymap.Yes, "yes").InBar(),
+		help.Bind(k, keymap.No, "no").InBar(),
+		help.Bind(k, keymap.Accept, "confirm").InBar(),
+		help.Interrupt.InBar(),
+		help.Bind(k, keymap.Help, "help").InBar(),
+	}
+}
+
+// accept stores the current choice and finishes
+func (c *Confirm) accept() widget.Result {
+	if c.Result != nil {
+		*c.Result = c.selected
+	}
+	return widget.Done(nil)
+}
+
+// answer returns the current choice as a word
+func (c *Confirm) answer() string {
+	if c.selected {
+		return "Yes"
+	}
+	return "No"
+}
+
+// DrawInline draws the question and the current choice on one line below
+// the previous output
+func (c *Confirm) DrawInline(r *renderer.Renderer) {
+	th := c.currentTheme()
+	
+	choices := "(y/N)"
+	if c.Default {
+		choices = "(Y/n)"
+	}
+	line := th.Success.Sprint("?") + " " + theme.Bold(c.Label) + " " + th.TextDim.Sprint(choices) + " " + th.Selected.Sprint(c.answer())
+	r.DrawInline([]string{line})
+}
 
+// runLine asks for a y/n answer on a plain line when there is no terminal.
+// An empty answer selects the default.
+func (c *Confirm) runLine(ctx context.Context) error {
+	th := c.currentTheme()
+	
+	choices := "[y/N]"
+	if c.Default {
+		choices = "[Y/n]"
+	}
+	
 	for {
-		c.render()
+		c.renderer.Write(th.Primary.Sprint(c.Label) + " " + th.TextDim.Sprint(choices) + ": ")
 		
-		event, err := renderer.ReadInput()
+		answer, err := c.renderer.ReadLineContext(ctx)
 		if err != nil {
 			return err
 		}
-
-		switch event.Key {
-		case renderer.KeyCtrlC:
-			return errors.New("cancelled")
-		
-		case renderer.KeyEnter:
-			if c.Result != nil {
-				*c.Result = c.selected
-			}
-			return nil
-		
-		case renderer.KeyArrowLeft, renderer.KeyArrowRight:
-			c.selected = !c.selected
-		
-		case renderer.KeyTab:
-			c.selected = !c.selected
 		
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "":
+			c.selected = c.Default
+		case "y", "yes":
+			c.selected = true
+		case "n", "no":
+			c.selected = false
 		default:
-			// Handle Y/N shortcuts
-			if event.Rune != 0 {
-				lower := strings.ToLower(string(event.Rune))
-				switch lower {
-				case "y":
-					c.selected = true
-					if c.Result != nil {
-						*c.Result = true
-					}
-					return nil
-				case "n":
-					c.selected = false
-					if c.Result != nil {
-						*c.Result = false
-					}
-					return nil
-				}
-			}
+			c.renderer.Write(th.Error.Sprint("✗ please answer y or n") + "\n")
+			continue
 		}
+		
+		if c.Result != nil {
+			*c.Result = c.selected
+		}
+		return nil
 	}
 }
 
-func (c *Confirm) render() {
-	c.renderer.Clear()
-	th := theme.Current()
+// Draw draws the question and the two choices in a box centered in area,
+// with the key help on the last rows
+func (c *Confirm) Draw(cv *renderer.Canvas, area renderer.Rect) {
+	th := c.currentTheme()
 	
 	// Calculate centered position
-	boxWidth := 40
+	boxWidth := min(40, area.Width)
 	boxHeight := 5
 	
-	startX := (c.renderer.Width() - boxWidth) / 2
-	startY := (c.renderer.Height() - boxHeight) / 2
+	startX := max(area.X+(area.Width-boxWidth)/2, area.X)
+	startY := max(area.Y+(area.Height-boxHeight)/2, area.Y)
 	
 	// Draw box
-	c.renderer.Box(startX, startY, boxWidth, boxHeight, "")
+	cv.StyledBox(renderer.Rect{X: startX, Y: startY, Width: boxWidth, Height: boxHeight}, "", th.Border.Sequence())
 	
 	// Label
 	labelY := startY + 1
-	c.renderer.PrintCentered(labelY, th.Primary.Sprint(c.Label))
+	cv.PrintCentered(labelY, th.Primary.Sprint(c.Label))
 	
 	// Options
 	optionsY := startY + 3
@@ -117,11 +264,12 @@ func (c *Confirm) render() {
 		noStyle = th.Selected
 	}
 	
-	c.renderer.Print(optionsX, optionsY, yesStyle.Sprint("  Yes (Y)  "))
-	c.renderer.Print(optionsX+12, optionsY, noStyle.Sprint("  No (N)  "))
+	cv.Print(optionsX, optionsY, yesStyle.Sprint("  Yes (Y)  "))
+	cv.Print(optionsX+12, optionsY, noStyle.Sprint("  No (N)  "))
 	
-	// Help text
-	helpY := c.renderer.Height() - 2
-	helpText := "←→/Tab to toggle • Y/N shortcuts • Enter to confirm • Ctrl+C to cancel"
-	c.renderer.PrintCentered(helpY, th.TextDim.Sprint(helpText))
-}
\ No newline at end of file
+	// Help text, when there is room left for it
+	helpY := area.Y + area.Height - 2
+	if helpY >= startY+boxHeight {
+		cv.PrintCentered(helpY, help.Bar(c.KeyBindings(), area.Width, th))
+	}
+}
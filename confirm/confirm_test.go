@@ -0,0 +1,97 @@
+package confirm_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/vynazevedo/termx/confirm"
+	"github.com/vynazevedo/termx/keymap"
+	"github.com/vynazevedo/termx/renderer"
+	"github.com/vynazevedo/termx/termxtest"
+	"github.com/vynazevedo/termx/widget"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		def    bool
+		keymap *keymap.Keymap
+		keys   []string
+		want   bool
+		err    error
+	}{
+		{"default no", false, nil, []string{termxtest.KeyEnter}, false, nil},
+		{"default yes", true, nil, []string{termxtest.KeyEnter}, true, nil},
+		{"toggle", false, nil, []string{termxtest.KeySpace, termxtest.KeyEnter}, true, nil},
+		{"arrows", true, nil, []string{termxtest.KeyLeft, termxtest.KeyRight, termxtest.KeyLeft, termxtest.KeyEnter}, false, nil},
+		{"y", false, nil, []string{"y"}, true, nil},
+		{"N", true, nil, []string{"N"}, false, nil},
+		{"ignored keys", false, nil, []string{"x", "y"}, true, nil},
+		{"vim moves", false, keymap.Vim(), []string{"l", termxtest.KeyEnter}, true, nil},
+		{"interrupt", true, nil, []string{termxtest.KeyCtrlC}, false, widget.ErrCancelled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term := termxtest.NewTerminal(60, 12)
+			term.Press(tt.keys...)
+
+			var result bool
+			c := confirm.New("Deploy?", &result).WithDefault(tt.def).WithRenderer(term.Renderer())
+			if tt.keymap != nil {
+				c.WithKeymap(tt.keymap)
+			}
+			if err := c.Run(); err != tt.err {
+				t.Fatalf("Run() error = %v, want %v", err, tt.err)
+			}
+			if result != tt.want {
+				t.Errorf("result = %v, want %v", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestInlineSummary(t *testing.T) {
+	term := termxtest.NewTerminal(60, 12)
+	term.Press("y")
+
+	var result bool
+	if err := confirm.New("Deploy?", &result).Inline().WithRenderer(term.Renderer()).Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	term.AssertLine(t, 0, "? Deploy?: Yes")
+	term.AssertNotContains(t, "(y/N)")
+}
+
+// Without a terminal the answer is read from a line of the input
+func TestRunLine(t *testing.T) {
+	tests := []struct {
+		input string
+		def   bool
+		want  bool
+		err   error
+	}{
+		{"y\n", false, true, nil},
+		{" NO \n", true, false, nil},
+		{"\n", true, true, nil},
+		{"maybe\nyes\n", false, true, nil},
+		{"", false, false, io.EOF},
+	}
+	for _, tt := range tests {
+		in, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, tt.input)
+		w.Close()
+
+		var out bytes.Buffer
+		var result bool
+		err = confirm.New("Deploy?", &result).WithDefault(tt.def).WithRenderer(renderer.NewWithIO(in, &out, nil)).Run()
+		in.Close()
+		if err != tt.err || result != tt.want {
+			t.Errorf("input %q: result %v with error %v, want %v with %v", tt.input, result, err, tt.want, tt.err)
+		}
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"log"
 	"time"
 	"github.com/vynazevedo/termx"
-	"github.com/vynazevedo/termx/renderer"
 )
 
 type Cluster struct {
@@ -32,7 +31,7 @@ func main() {
 	
 	termx.ASCII(termx.KubernetesLogo).WithColor("\033[34m").Render()
 	fmt.Println("\nGerenciador de Cluster Kubernetes v1.0")
-	fmt.Println("======================================\n")
+	fmt.Print("======================================\n\n")
 	
 	var action string
 	err := termx.Select("O que você gostaria de fazer?", []string{
@@ -75,7 +74,7 @@ func showClusterOverview() {
 	}
 	
 	fmt.Println("Visão Geral do Cluster")
-	fmt.Println("======================\n")
+	fmt.Print("======================\n\n")
 	
 	table := termx.Table([]string{"Cluster", "Status", "Nós", "Pods", "CPU %", "Memória %", "Região"})
 	
@@ -126,7 +125,7 @@ func managePods() {
 	}
 	
 	fmt.Println("Pod Management")
-	fmt.Println("==============\n")
+	fmt.Print("==============\n\n")
 	
 	table := termx.Table([]string{"Pod Name", "Namespace", "Status", "Restarts", "CPU", "Memory"})
 	table.Interactive()
@@ -155,7 +154,7 @@ func managePod(pod Pod) {
 	termx.ClearScreen()
 	
 	fmt.Printf("Managing Pod: %s\n", pod.Name)
-	fmt.Println("==================\n")
+	fmt.Print("==================\n\n")
 	
 	var action string
 	termx.Select("Select action:", []string{
@@ -199,7 +198,7 @@ func scaleDeployment() {
 	termx.ClearScreen()
 	
 	fmt.Println("Scale Deployment")
-	fmt.Println("================\n")
+	fmt.Print("================\n\n")
 	
 	var deployment string
 	var replicas string
@@ -229,7 +228,7 @@ func showResourceUsage() {
 	termx.ClearScreen()
 	
 	fmt.Println("Resource Usage Overview")
-	fmt.Println("======================\n")
+	fmt.Print("======================\n\n")
 	
 	fmt.Println("CPU Usage (last hour):")
 	cpuData := []float64{45, 52, 48, 65, 72, 68, 71, 69, 73, 78, 82, 79}
@@ -250,7 +249,7 @@ func deployApplication() {
 	termx.ClearScreen()
 	
 	fmt.Println("Deploy New Application")
-	fmt.Println("=====================\n")
+	fmt.Print("=====================\n\n")
 	
 	var (
 		appName   string
@@ -327,14 +326,9 @@ func deployApplication() {
 }
 
 func manageCluster(clusterName string) {
-	termx.ClearScreen()
-	
-	fmt.Printf("Managing Cluster: %s\n", clusterName)
-	fmt.Println("====================\n")
-	
 	layout := termx.Split("horizontal").WithRatio(0.3)
 	
-	leftBox := termx.BoxLayout("Quick Stats")
+	leftBox := termx.BoxLayout(clusterName + " - Quick Stats")
 	leftBox.SetContent(fmt.Sprintf(
 		"Nodes: 15\nPods: 287\nServices: 42\nIngresses: 18\n\nAlerts: 3",
 	))
@@ -350,7 +344,7 @@ func manageCluster(clusterName string) {
 	
 	layout.SetLeft(leftBox).SetRight(rightBox)
 	
-	r := renderer.New()
-	defer r.Close()
-	layout.Render(r)
+	if err := layout.Run(); err != nil {
+		fmt.Printf("Error managing cluster %s: %v\n", clusterName, err)
+	}
 }
\ No newline at end of file
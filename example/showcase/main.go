@@ -17,7 +17,7 @@ func main() {
  ╩ └─┘┴└─┴ ┴╚═╝  ╩ `).WithColor("\033[36m").Render()
 	
 	fmt.Println("\nBiblioteca Avançada de Interface Terminal")
-	fmt.Println("==========================================\n")
+	fmt.Print("==========================================\n\n")
 	
 	var demo string
 	err := termx.Select("Escolha uma demonstração:", []string{
@@ -56,7 +56,7 @@ func dataDashboard() {
 	termx.ClearScreen()
 	
 	fmt.Println("Painel de Análises")
-	fmt.Println("==================\n")
+	fmt.Print("==================\n\n")
 	
 	fmt.Println("Usuários Ativos Diários:")
 	users := []float64{1250, 1380, 1420, 1350, 1580, 1690, 1750}
@@ -88,7 +88,7 @@ func serverMonitor() {
 	termx.ASCII(termx.ServerRack).WithColor("\033[33m").Render()
 	
 	fmt.Println("\nMonitoramento do Servidor")
-	fmt.Println("========================\n")
+	fmt.Print("========================\n\n")
 	
 	servers := []struct {
 		name   string
@@ -133,7 +133,7 @@ func gitClient() {
 	termx.ClearScreen()
 	
 	fmt.Println("Gerenciador de Repositório Git")
-	fmt.Println("===============================\n")
+	fmt.Print("===============================\n\n")
 	
 	var action string
 	termx.Select("O que você gostaria de fazer?", []string{
@@ -200,7 +200,7 @@ func databaseManager() {
 	termx.ClearScreen()
 	
 	fmt.Println("Gerenciador de Banco de Dados")
-	fmt.Println("=============================\n")
+	fmt.Print("=============================\n\n")
 	
 	var (
 		host     string
@@ -223,7 +223,7 @@ func databaseManager() {
 	time.Sleep(2 * time.Second)
 	spinner.Stop()
 	
-	fmt.Println("✓ Conectado com sucesso\n")
+	fmt.Print("✓ Conectado com sucesso\n\n")
 	
 	fmt.Println("Tabelas no banco de dados:")
 	table := termx.Table([]string{"Tabela", "Linhas", "Tamanho", "Última Modificação"}).Interactive()
@@ -242,7 +242,7 @@ func fileExplorer() {
 	termx.ClearScreen()
 	
 	fmt.Println("File Explorer")
-	fmt.Println("=============\n")
+	fmt.Print("=============\n\n")
 	
 	currentPath := "/home/user/projects"
 	
@@ -270,13 +270,13 @@ func fileExplorer() {
 			currentPath = "/home/usuario"
 			termx.ClearScreen()
 			fmt.Println("Explorador de Arquivos")
-			fmt.Println("=====================\n")
+			fmt.Print("=====================\n\n")
 		} else if strings.HasPrefix(selected, "[DIR]") {
 			folder := selected[6:len(selected)-1]
 			currentPath = currentPath + "/" + folder
 			termx.ClearScreen()
 			fmt.Println("Explorador de Arquivos")
-			fmt.Println("=====================\n")
+			fmt.Print("=====================\n\n")
 		} else {
 			fmt.Printf("\nArquivo: %s\n", selected[7:])
 			fmt.Println("Tamanho: 1.2 KB")
@@ -289,7 +289,7 @@ func fileExplorer() {
 			if action == "Voltar" {
 				termx.ClearScreen()
 				fmt.Println("Explorador de Arquivos")
-				fmt.Println("=====================\n")
+				fmt.Print("=====================\n\n")
 			}
 		}
 	}
@@ -299,7 +299,7 @@ func taskManager() {
 	termx.ClearScreen()
 	
 	fmt.Println("Gerenciador de Tarefas")
-	fmt.Println("=====================\n")
+	fmt.Print("=====================\n\n")
 	
 	fmt.Println("Visão Geral do Sistema:")
 	fmt.Printf("Uso de CPU: ")
@@ -1,25 +1,64 @@
 package form
 
 import (
+	"context"
+
 	"github.com/vynazevedo/termx/confirm"
 	"github.com/vynazevedo/termx/input"
+	"github.com/vynazevedo/termx/keymap"
+	"github.com/vynazevedo/termx/renderer"
 	"github.com/vynazevedo/termx/selector"
+	"github.com/vynazevedo/termx/theme"
 )
 
 type Form struct {
-	steps []Step
+	steps    []Step
+	renderer *renderer.Renderer
+	inline   bool
+	keymap   *keymap.Keymap
+	theme    *theme.Theme
 }
 
 type Step interface {
 	Run() error
 }
 
+// contextStep is a Step that can be cancelled through a context
+type contextStep interface {
+	RunContext(ctx context.Context) error
+}
+
 func New() *Form {
 	return &Form{
 		steps: []Step{},
 	}
 }
 
+// WithRenderer runs every step of the form on r
+func (f *Form) WithRenderer(r *renderer.Renderer) *Form {
+	f.renderer = r
+	return f
+}
+
+// WithKeymap makes every built-in step resolve keys through k
+func (f *Form) WithKeymap(k *keymap.Keymap) *Form {
+	f.keymap = k
+	return f
+}
+
+// WithTheme draws every built-in step with t
+func (f *Form) WithTheme(t *theme.Theme) *Form {
+	f.theme = t
+	return f
+}
+
+// Inline renders every step below the previous output instead of taking
+// over the screen, leaving a one-line summary of each answer behind
+func (f *Form) Inline() *Form {
+	f.inline = true
+	return f
+}
+
 func (f *Form) Input(label string, value *string) *Form {
 	f.steps = append(f.steps, input.New(label, value))
 	return f
@@ -50,14 +89,76 @@ func (f *Form) Confirm(label string, result *bool) *Form {
 }
 
 func (f *Form) Run() error {
+	return f.RunContext(context.Background())
+}
+
+// RunContext runs the steps in order and stops at the first error. Steps
+// that have a RunContext method receive ctx; the form also stops before the
+// next step once ctx is done.
+func (f *Form) RunContext(ctx context.Context) error {
 	for _, step := range f.steps {
-		if err := step.Run(); err != nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		f.configure(step)
+		var err error
+		if s, ok := step.(contextStep); ok {
+			err = s.RunContext(ctx)
+		} else {
+			err = step.Run()
+		}
+		if err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// configure applies the form-wide options to the built-in steps
+func (f *Form) configure(step Step) {
+	switch s := step.(type) {
+	case *input.Input:
+		if f.renderer != nil {
+			s.WithRenderer(f.renderer)
+		}
+		if f.inline {
+			s.Inline()
+		}
+		if f.keymap != nil {
+			s.WithKeymap(f.keymap)
+		}
+		if f.theme != nil {
+			s.WithTheme(f.theme)
+		}
+	case *selector.Select:
+		if f.renderer != nil {
+			s.WithRenderer(f.renderer)
+		}
+		if f.inline {
+			s.Inline()
+		}
+		if f.keymap != nil {
+			s.WithKeymap(f.keymap)
+		}
+		if f.theme != nil {
+			s.WithTheme(f.theme)
+		}
+	case *confirm.Confirm:
+		if f.renderer != nil {
+			s.WithRenderer(f.renderer)
+		}
+		if f.inline {
+			s.Inline()
+		}
+		if f.keymap != nil {
+			s.WithKeymap(f.keymap)
+		}
+		if f.theme != nil {
+			s.WithTheme(f.theme)
+		}
+	}
+}
+
 // Helper functions for input options
 func WithPlaceholder(placeholder string) func(*input.Input) {
 	return func(i *input.Input) {
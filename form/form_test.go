@@ -0,0 +1,77 @@
+package form_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/vynazevedo/termx/form"
+	"github.com/vynazevedo/termx/termxtest"
+)
+
+func TestRun(t *testing.T) {
+	term := termxtest.NewTerminal(60, 20)
+	term.Type("gopher")
+	term.Press(termxtest.KeyEnter)
+	term.Press(termxtest.KeyDown, termxtest.KeyEnter)
+	term.Press("y")
+
+	var name, editor string
+	var ok bool
+	err := form.New().
+		Input("Name", &name).
+		Select("Editor", []string{"vim", "emacs", "nano"}, &editor).
+		Confirm("Save?", &ok).
+		WithRenderer(term.Renderer()).
+		Run()
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if name != "gopher" || editor != "emacs" || !ok {
+		t.Errorf("answers = %q, %q, %v, want %q, %q, %v", name, editor, ok, "gopher", "emacs", true)
+	}
+}
+
+// The form stops at the first step that fails
+func TestRunStopsOnError(t *testing.T) {
+	term := termxtest.NewTerminal(60, 20)
+	term.Type("gopher")
+
+	var name, editor string
+	err := form.New().
+		Input("Name", &name).
+		Select("Editor", []string{"vim", "emacs"}, &editor).
+		WithRenderer(term.Renderer()).
+		Run()
+	if err != io.EOF {
+		t.Fatalf("Run() error = %v, want %v", err, io.EOF)
+	}
+	if name != "" || editor != "" {
+		t.Errorf("answers = %q, %q, want none", name, editor)
+	}
+}
+
+func TestRunContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var name string
+	err := form.New().Input("Name", &name).WithRenderer(termxtest.NewTerminal(60, 20).Renderer()).RunContext(ctx)
+	if err != context.Canceled {
+		t.Errorf("RunContext() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestInline(t *testing.T) {
+	term := termxtest.NewTerminal(60, 20)
+	term.Type("gopher")
+	term.Press(termxtest.KeyEnter, "n")
+
+	var name string
+	var ok bool
+	if err := form.New().Input("Name", &name).Confirm("Save?", &ok).Inline().WithRenderer(term.Renderer()).Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	term.AssertContains(t, "? Name: gopher")
+	term.AssertContains(t, "? Save?: No")
+}
@@ -0,0 +1,167 @@
+// Package help documents the keys of the interactive components. It renders
+// a one-line bar with the most used bindings and a full-screen overlay,
+// opened with the help action ("?" or F1 by default), listing them all by
+// category. Both are generated from the component's keymap, so they follow
+// whatever the keys were rebound to.
+package help
+
+import (
+	"strings"
+
+	"github.com/vynazevedo/termx/keymap"
+	"github.com/vynazevedo/termx/renderer"
+	"github.com/vynazevedo/termx/theme"
+)
+
+// Categories are the groups of the overlay, in order
+var Categories = []string{"Navigation", "Selection", "Filter", "Editing", "Actions", "General"}
+
+// Binding documents what a set of keys does in a component
+type Binding struct {
+	Action   keymap.Action // the action the keys trigger, if any
+	Keys     []keymap.Chord
+	Help     string
+	Category string
+	Short    bool // shown in the bar as well as in the overlay
+}
+
+// Interrupt documents Ctrl+C, which cancels any component
+var Interrupt = Binding{
+	Action:   keymap.Cancel,
+	Keys:     []keymap.Chord{keymap.Interrupt},
+	Help:     "cancel",
+	Category: "Actions",
+}
+
+// Bind documents action with the keys bound to it in k
+func Bind(k *keymap.Keymap, action keymap.Action, help string) Binding {
+	return Binding{
+		Action:   action,
+		Keys:     k.Keys(action),
+		Help:     help,
+		Category: category(action),
+	}
+}
+
+// category returns the overlay group of action
+func category(action keymap.Action) string {
+	switch {
+	case strings.HasPrefix(string(action), "cursor."), strings.HasPrefix(string(action), "page."):
+		return "Navigation"
+	case action == keymap.Toggle, strings.HasPrefix(string(action), "select."):
+		return "Selection"
+	case strings.HasPrefix(string(action), "filter."):
+		return "Filter"
+	case strings.HasPrefix(string(action), "delete."), action == keymap.Complete:
+		return "Editing"
+	case action == keymap.Help:
+		return "General"
+	}
+	return "Actions"
+}
+
+// InBar marks the binding to be shown in the bar
+func (b Binding) InBar() Binding {
+	b.Short = true
+	return b
+}
+
+// TextEntry drops the plain characters from the keys, which a component
+// taking text inserts instead of treating as bindings
+func (b Binding) TextEntry() Binding {
+	var keys []keymap.Chord
+	for _, chord := range b.Keys {
+		if !chord.Plain() {
+			keys = append(keys, chord)
+		}
+	}
+	b.Keys = keys
+	return b
+}
+
+// keys returns the keys of the binding as help text shows them
+func (b Binding) keys() string {
+	names := make([]string, len(b.Keys))
+	for i, chord := range b.Keys {
+		names[i] = chord.String()
+	}
+	return strings.Join(names, "/")
+}
+
+// Bar returns the bindings marked with InBar on one line, as "key help"
+// pairs, cut to width. Pairs that do not fit are replaced with an ellipsis.
+// Bindings left without keys, including keys an earlier binding already
+// documents, are left out. A nil theme stands for theme.Current().
+func Bar(bindings []Binding, width int, th *theme.Theme) string {
+	if th == nil {
+		th = theme.Current()
+	}
+	sep := " • "
+
+	bindings = effective(bindings)
+	var out strings.Builder
+	used := 0
+	for i, b := range bindings {
+		if !b.Short || len(b.Keys) == 0 {
+			continue
+		}
+
+		need := renderer.StringWidth(b.keys()) + 1 + renderer.StringWidth(b.Help)
+		if used > 0 {
+			need += renderer.StringWidth(sep)
+		}
+		// Room must be left for the ellipsis unless this is the last pair
+		reserve := 0
+		if hasMore(bindings[i+1:]) {
+			reserve = 2
+		}
+		if used+need+reserve > width {
+			switch {
+			case used == 0 && width > 0:
+				out.WriteString(th.HelpText.Sprint("…"))
+			case used > 0 && used+2 <= width:
+				out.WriteString(th.HelpText.Sprint(" …"))
+			}
+			break
+		}
+
+		if used > 0 {
+			out.WriteString(th.HelpText.Sprint(sep))
+		}
+		out.WriteString(th.HelpKey.Sprint(b.keys()) + " " + th.HelpText.Sprint(b.Help))
+		used += need
+	}
+	return out.String()
+}
+
+// effective drops the keys already documented by an earlier binding.
+// Components list their bindings in the order they resolve keys, so a key
+// bound to several actions does what its first binding says.
+func effective(bindings []Binding) []Binding {
+	seen := make(map[keymap.Chord]bool)
+	out := make([]Binding, len(bindings))
+	for i, b := range bindings {
+		var keys []keymap.Chord
+		for _, chord := range b.Keys {
+			if !seen[chord] {
+				keys = append(keys, chord)
+			}
+		}
+		for _, chord := range b.Keys {
+			seen[chord] = true
+		}
+		b.Keys = keys
+		out[i] = b
+	}
+	return out
+}
+
+// hasMore reports whether any of bindings would be shown in the bar
+func hasMore(bindings []Binding) bool {
+	for _, b := range bindings {
+		if b.Short && len(b.Keys) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package help_test
+
+import (
+	"testing"
+
+	"github.com/vynazevedo/termx/help"
+	"github.com/vynazevedo/termx/keymap"
+	"github.com/vynazevedo/termx/renderer"
+	"github.com/vynazevedo/termx/termxtest"
+	"github.com/vynazevedo/termx/theme"
+	"github.com/vynazevedo/termx/widget"
+)
+
+// list is a widget documenting the keys of a filtering list
+type list struct {
+	keys   *keymap.Keymap
+	events int
+}
+
+func (l *list) Draw(c *renderer.Canvas, area renderer.Rect) { c.Print(area.X, area.Y, "list") }
+func (l *list) Focusable() bool                             { return true }
+
+func (l *list) HandleEvent(event *renderer.InputEvent) widget.Result {
+	l.events++
+	return widget.Handled
+}
+
+func (l *list) KeyBindings() []help.Binding {
+	return []help.Binding{
+		help.Bind(l.keys, keymap.CursorUp, "up").InBar(),
+		help.Bind(l.keys, keymap.CursorDown, "down").InBar(),
+		help.Bind(l.keys, keymap.Accept, "select").InBar(),
+		help.Bind(l.keys, keymap.FilterClear, "clear").InBar(),
+		help.Bind(l.keys, keymap.Cancel, "quit").InBar(),
+		help.Bind(l.keys, keymap.CursorHome, "first"),
+		help.Bind(l.keys, keymap.Help, "help").InBar(),
+	}
+}
+
+func TestBar(t *testing.T) {
+	tests := []struct {
+		preset string
+		width  int
+		want   string
+	}{
+		{"default", 80, "↑ up • ↓ down • Enter select • Esc/c clear • ?/F1 help"},
+		{"default", 40, "↑ up • ↓ down • Enter select …"},
+		{"default", 8, "↑ up …"},
+		{"default", 3, "…"},
+		{"default", 0, ""},
+		{"vim", 80, "↑/k up • ↓/j down • Enter select • Esc/c clear • q quit • ?/F1 help"},
+		{"emacs", 80, "↑/Ctrl+P up • ↓/Ctrl+N down • Enter select • Esc/c/Ctrl+U clear • Ctrl+G quit …"},
+	}
+	for _, tt := range tests {
+		k, _ := keymap.Preset(tt.preset)
+		bar := help.Bar((&list{keys: k}).KeyBindings(), tt.width, theme.Default)
+		if got := renderer.StripANSI(bar); got != tt.want {
+			t.Errorf("%s: Bar(width %d) = %q, want %q", tt.preset, tt.width, got, tt.want)
+		}
+		if w := renderer.StringWidth(bar); w > tt.width {
+			t.Errorf("%s: Bar(width %d) is %d cells wide", tt.preset, tt.width, w)
+		}
+	}
+}
+
+func TestTextEntry(t *testing.T) {
+	b := help.Bind(keymap.Vim(), keymap.CursorDown, "down").TextEntry()
+	if len(b.Keys) != 1 || b.Keys[0].Key != renderer.KeyArrowDown {
+		t.Errorf("TextEntry() kept %v, want only ↓", b.Keys)
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	l := &list{keys: keymap.Default()}
+	o := help.WithOverlay(l).WithTheme(theme.Default)
+
+	steps := []struct {
+		event  *renderer.InputEvent
+		open   bool
+		events int
+	}{
+		{&renderer.InputEvent{Rune: 'x'}, false, 1},
+		{&renderer.InputEvent{Rune: '?'}, true, 1},
+		{&renderer.InputEvent{Key: renderer.KeyMouse}, true, 1},
+		{&renderer.InputEvent{Rune: 'x'}, false, 1},
+		{&renderer.InputEvent{Key: renderer.KeyF1}, true, 1},
+	}
+	for i, step := range steps {
+		o.HandleEvent(step.event)
+		if o.Open() != step.open || l.events != step.events {
+			t.Errorf("step %d: open %v with %d events passed on, want %v with %d", i, o.Open(), l.events, step.open, step.events)
+		}
+	}
+
+	term := termxtest.NewTerminal(40, 20)
+	r := term.Renderer()
+	r.Clear()
+	c := r.Canvas()
+	o.Draw(c, c.Bounds())
+	r.Flush()
+	term.AssertGolden(t, "overlay")
+}
@@ -0,0 +1,133 @@
+package help
+
+import (
+	"github.com/vynazevedo/termx/keymap"
+	"github.com/vynazevedo/termx/renderer"
+	"github.com/vynazevedo/termx/theme"
+	"github.com/vynazevedo/termx/widget"
+)
+
+// Provider is a widget that documents its keys
+type Provider interface {
+	widget.Widget
+
+	// KeyBindings returns the bindings of the widget in its current state,
+	// most used first
+	KeyBindings() []Binding
+}
+
+// Overlay runs a widget and covers it with the full list of its bindings
+// when one of the keys documented for keymap.Help is pressed. The next key
+// closes the list again.
+type Overlay struct {
+	widget Provider
+	open   bool
+	theme  *theme.Theme
+}
+
+// WithOverlay wraps p so that its help action opens the overlay
+func WithOverlay(p Provider) *Overlay {
+	return &Overlay{widget: p}
+}
+
+// WithTheme draws the overlay with t instead of theme.Current()
+func (o *Overlay) WithTheme(t *theme.Theme) *Overlay {
+	o.theme = t
+	return o
+}
+
+// Open reports whether the overlay is shown
+func (o *Overlay) Open() bool {
+	return o.open
+}
+
+func (o *Overlay) Focusable() bool {
+	return o.widget.Focusable()
+}
+
+// KeyBindings returns the bindings of the wrapped widget
+func (o *Overlay) KeyBindings() []Binding {
+	return o.widget.KeyBindings()
+}
+
+func (o *Overlay) Draw(c *renderer.Canvas, area renderer.Rect) {
+	if o.open {
+		Draw(c, area, o.widget.KeyBindings(), o.theme)
+		return
+	}
+	o.widget.Draw(c, area)
+}
+
+// HandleEvent closes the overlay on any key while it is shown, opens it on
+// the help keys and passes every other event to the wrapped widget
+func (o *Overlay) HandleEvent(event *renderer.InputEvent) widget.Result {
+	if o.open {
+		if event.Key != renderer.KeyMouse {
+			o.open = false
+			return widget.Handled
+		}
+		return widget.Ignored
+	}
+
+	for _, b := range o.widget.KeyBindings() {
+		if b.Action != keymap.Help {
+			continue
+		}
+		for _, chord := range b.Keys {
+			if chord.Matches(event) {
+				o.open = true
+				return widget.Handled
+			}
+		}
+	}
+	return o.widget.HandleEvent(event)
+}
+
+// Draw draws bindings grouped by category in a box centered in area. A key
+// listed by several bindings is only shown with the first. A nil theme
+// stands for theme.Current().
+func Draw(c *renderer.Canvas, area renderer.Rect, bindings []Binding, th *theme.Theme) {
+	if th == nil {
+		th = theme.Current()
+	}
+	bindings = effective(bindings)
+
+	keyWidth := 0
+	for _, b := range bindings {
+		keyWidth = max(keyWidth, renderer.StringWidth(b.keys()))
+	}
+
+	var lines []string
+	for _, cat := range Categories {
+		var group []string
+		for _, b := range bindings {
+			if b.Category != cat || len(b.Keys) == 0 {
+				continue
+			}
+			group = append(group, "  "+th.HelpKey.Sprint(renderer.PadRight(b.keys(), keyWidth))+"  "+b.Help)
+		}
+		if len(group) == 0 {
+			continue
+		}
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, theme.Bold(cat))
+		lines = append(lines, group...)
+	}
+	footer := th.HelpText.Sprint("Press any key to close")
+	lines = append(lines, "", footer)
+
+	width := 0
+	for _, line := range lines {
+		width = max(width, renderer.StringWidth(line))
+	}
+	box := area.Center(width+4, len(lines)+2)
+
+	c.Fill(area, "")
+	c.StyledBox(box, " Help ", th.Border.Sequence())
+	inner := c.Clip(box.Inset(1))
+	for i, line := range lines {
+		inner.Print(box.X+2, box.Y+1+i, line)
+	}
+}
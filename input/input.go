@@ -1,13 +1,18 @@
 package input
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
 	"unicode/utf8"
 
+	"github.com/vynazevedo/termx/help"
+	"github.com/vynazevedo/termx/keymap"
 	"github.com/vynazevedo/termx/renderer"
 	"github.com/vynazevedo/termx/theme"
+	"github.com/vynazevedo/termx/textwidth"
+	"github.com/vynazevedo/termx/widget"
 )
 
 type Input struct {
@@ -22,6 +27,20 @@ type Input struct {
 	cursorPos   int
 	renderer    *renderer.Renderer
 	error       string
+	inline      bool
+	keymap      *keymap.Keymap
+	theme       *theme.Theme
+}
+
+// actions are the keymap actions the input responds to
+var actions = []keymap.Action{
+	keymap.Accept,
+	keymap.DeleteBackward,
+	keymap.DeleteForward,
+	keymap.CursorLeft,
+	keymap.CursorRight,
+	keymap.CursorHome,
+	keymap.CursorEnd,
 }
 
 func New(label string, value *string) *Input {
@@ -53,142 +72,314 @@ func (i *Input) Password() *Input {
 	return i
 }
 
-func (i *Input) Run() error {
-	i.renderer = renderer.New()
-	if err := i.renderer.Init(); err != nil {
-		return err
+// WithRenderer draws the input with r instead of a renderer on the standard streams
+func (i *Input) WithRenderer(r *renderer.Renderer) *Input {
+	i.renderer = r
+	return i
+}
+
+// WithKeymap resolves keys through k instead of keymap.Current()
+func (i *Input) WithKeymap(k *keymap.Keymap) *Input {
+	i.keymap = k
+	return i
+}
+
+// keys returns the keymap the input resolves keys through
+func (i *Input) keys() *keymap.Keymap {
+	if i.keymap != nil {
+		return i.keymap
+	}
+	return keymap.Current()
+}
+
+// WithTheme draws the input with t instead of theme.Current()
+func (i *Input) WithTheme(t *theme.Theme) *Input {
+	i.theme = t
+	return i
+}
+
+// currentTheme returns the theme the input is drawn with
+func (i *Input) currentTheme() *theme.Theme {
+	if i.theme != nil {
+		return i.theme
 	}
-	defer i.renderer.Restore()
+	return theme.Current()
+}
 
+// Inline renders the input below the previous output at the cursor
+// position instead of taking over the screen. Once answered it collapses
+// to a one-line summary.
+func (i *Input) Inline() *Input {
+	i.inline = true
+	return i
+}
+
+func (i *Input) Run() error {
+	return i.RunContext(context.Background())
+}
+
+// RunContext is like Run but stops waiting for the user and returns
+// ctx.Err() once ctx is done
+func (i *Input) RunContext(ctx context.Context) error {
+	if i.renderer == nil {
+		i.renderer = renderer.New()
+	}
 	if i.Value != nil && *i.Value != "" {
 		i.buffer = []rune(*i.Value)
 		i.cursorPos = len(i.buffer)
 	}
 
+	var err error
+	if i.inline {
+		err = widget.RunInline(ctx, i.renderer, i)
+	} else {
+		err = widget.Run(ctx, i.renderer, help.WithOverlay(i).WithTheme(i.theme))
+	}
+	if errors.Is(err, renderer.ErrNotTerminal) {
+		return i.runLine(ctx)
+	}
+	if err == nil && i.inline {
+		i.collapse()
+	}
+	return err
+}
+
+// Focusable reports that the input takes keyboard input
+func (i *Input) Focusable() bool {
+	return true
+}
+
+// HandleEvent edits the value and finishes on Enter once the value passes
+// the validator
+func (i *Input) HandleEvent(event *renderer.InputEvent) widget.Result {
+	if event.Key == renderer.KeyCtrlC {
+		return widget.Done(widget.ErrCancelled)
+	}
+	
+	switch i.keys().Lookup(event, true, actions...) {
+	case keymap.Accept:
+		value := string(i.buffer)
+		if i.Validator != nil {
+			if err := i.Validator(value); err != nil {
+				i.error = err.Error()
+				return widget.Handled
+			}
+		}
+		if i.Value != nil {
+			*i.Value = value
+		}
+		return widget.Done(nil)
+	
+	case keymap.DeleteBackward:
+		if i.cursorPos > 0 {
+			i.buffer = append(i.buffer[:i.cursorPos-1], i.buffer[i.cursorPos:]...)
+			i.cursorPos--
+			i.error = ""
+		}
+	
+	case keymap.DeleteForward:
+		if i.cursorPos < len(i.buffer) {
+			i.buffer = append(i.buffer[:i.cursorPos], i.buffer[i.cursorPos+1:]...)
+			i.error = ""
+		}
+	
+	case keymap.CursorLeft:
+		if i.cursorPos > 0 {
+			i.cursorPos--
+		}
+	
+	case keymap.CursorRight:
+		if i.cursorPos < len(i.buffer) {
+			i.cursorPos++
+		}
+	
+	case keymap.CursorHome:
+		i.cursorPos = 0
+	
+	case keymap.CursorEnd:
+		i.cursorPos = len(i.buffer)
+	
+	default:
+		switch {
+		case event.Key == renderer.KeyPaste:
+			i.insert([]rune(renderer.SingleLine(event.Text)))
+		case event.Printable():
+			i.insert([]rune{event.Rune})
+		default:
+			return widget.Ignored
+		}
+	}
+	return widget.Handled
+}
+
+// KeyBindings lists the keys of the input, for help
+func (i *Input) KeyBindings() []help.Binding {
+	k := i.keys()
+	bindings := []help.Binding{
+		help.Bind(k, keymap.Accept, "confirm").InBar(),
+		help.Interrupt.InBar(),
+		help.Bind(k, keymap.CursorLeft, "move left"),
+		help.Bind(k, keymap.CursorRight, "move right"),
+		help.Bind(k, keymap.CursorHome, "go to start"),
+		help.Bind(k, keymap.CursorEnd, "go to end"),
+		help.Bind(k, keymap.DeleteBackward, "delete before cursor"),
+		help.Bind(k, keymap.DeleteForward, "delete at cursor"),
+		help.Bind(k, keymap.Help, "help").InBar(),
+	}
+	for n := range bindings {
+		bindings[n] = bindings[n].TextEntry()
+	}
+	return bindings
+}
+
+// runLine asks for the value on a plain line when there is no terminal. An
+// empty answer keeps the current value.
+func (i *Input) runLine(ctx context.Context) error {
+	th := i.currentTheme()
+	
+	prompt := i.Label
+	if i.Value != nil && *i.Value != "" && !i.Mask {
+		prompt += " [" + *i.Value + "]"
+	} else if i.Placeholder != "" {
+		prompt += " (" + i.Placeholder + ")"
+	}
+	
 	for {
-		i.render()
+		i.renderer.Write(th.Primary.Sprint(prompt) + ": ")
 		
-		event, err := renderer.ReadInput()
+		value, err := i.renderer.ReadLineContext(ctx)
 		if err != nil {
 			return err
 		}
-
-		switch event.Key {
-		case renderer.KeyCtrlC:
-			return errors.New("cancelled")
-		
-		case renderer.KeyEnter:
-			value := string(i.buffer)
-			if i.Validator != nil {
-				if err := i.Validator(value); err != nil {
-					i.error = err.Error()
-					continue
-				}
-			}
-			if i.Value != nil {
-				*i.Value = value
-			}
-			return nil
-		
-		case renderer.KeyBackspace:
-			if i.cursorPos > 0 {
-				i.buffer = append(i.buffer[:i.cursorPos-1], i.buffer[i.cursorPos:]...)
-				i.cursorPos--
-				i.error = ""
-			}
-		
-		case renderer.KeyDelete:
-			if i.cursorPos < len(i.buffer) {
-				i.buffer = append(i.buffer[:i.cursorPos], i.buffer[i.cursorPos+1:]...)
-				i.error = ""
-			}
-		
-		case renderer.KeyArrowLeft:
-			if i.cursorPos > 0 {
-				i.cursorPos--
-			}
-		
-		case renderer.KeyArrowRight:
-			if i.cursorPos < len(i.buffer) {
-				i.cursorPos++
-			}
-		
-		case renderer.KeyHome:
-			i.cursorPos = 0
+		if value == "" && i.Value != nil {
+			value = *i.Value
+		}
 		
-		case renderer.KeyEnd:
-			i.cursorPos = len(i.buffer)
+		if i.MaxLength > 0 && utf8.RuneCountInString(value) > i.MaxLength {
+			err = fmt.Errorf("must be at most %d characters", i.MaxLength)
+		} else if i.Validator != nil {
+			err = i.Validator(value)
+		}
+		if err != nil {
+			i.renderer.Write(th.Error.Sprint("✗ "+err.Error()) + "\n")
+			continue
+		}
 		
-		default:
-			if event.Rune != 0 {
-				if i.MaxLength == 0 || len(i.buffer) < i.MaxLength {
-					i.buffer = append(i.buffer[:i.cursorPos], append([]rune{event.Rune}, i.buffer[i.cursorPos:]...)...)
-					i.cursorPos++
-					i.error = ""
-				}
-			}
+		if i.Value != nil {
+			*i.Value = value
 		}
+		return nil
 	}
 }
 
-func (i *Input) render() {
-	i.renderer.Clear()
-	th := theme.Current()
+// insert adds text at the cursor, truncated to what still fits in MaxLength
+func (i *Input) insert(text []rune) {
+	if i.MaxLength > 0 && len(i.buffer)+len(text) > i.MaxLength {
+		text = text[:max(i.MaxLength-len(i.buffer), 0)]
+	}
+	if len(text) == 0 {
+		return
+	}
+	
+	buffer := make([]rune, 0, len(i.buffer)+len(text))
+	buffer = append(buffer, i.buffer[:i.cursorPos]...)
+	buffer = append(buffer, text...)
+	buffer = append(buffer, i.buffer[i.cursorPos:]...)
+	i.buffer = buffer
+	i.cursorPos += len(text)
+	i.error = ""
+}
+
+// display returns the buffer as shown on screen, masked for passwords
+func (i *Input) display() []rune {
+	if i.Mask {
+		return []rune(strings.Repeat("•", len(i.buffer)))
+	}
+	return i.buffer
+}
+
+// DrawInline draws the prompt and any error as lines below the previous output
+func (i *Input) DrawInline(r *renderer.Renderer) {
+	th := i.currentTheme()
+	
+	prefix := th.Success.Sprint("?") + " " + theme.Bold(i.Label) + ": "
+	displayRunes := i.display()
+	
+	line := prefix + string(displayRunes)
+	if len(i.buffer) == 0 && i.Placeholder != "" {
+		line = prefix + th.Placeholder.Sprint(i.Placeholder)
+	}
+	lines := []string{line}
+	if i.error != "" {
+		lines = append(lines, th.Error.Sprint("✗ "+i.error))
+	}
+	
+	cursorX := renderer.StringWidth(prefix) + textwidth.String(string(displayRunes[:i.cursorPos]))
+	r.SetCursor(cursorX, 0)
+	r.DrawInline(lines)
+}
+
+// collapse replaces the inline region with a summary of the answer
+func (i *Input) collapse() {
+	th := i.currentTheme()
+	i.renderer.ClearInline()
+	i.renderer.Write(th.Success.Sprint("?") + " " + theme.Bold(i.Label) + ": " + th.Primary.Sprint(string(i.display())) + "\n")
+}
+
+// Draw draws the label and a boxed field centered in area, with any error
+// below it and the key help on the last rows
+func (i *Input) Draw(c *renderer.Canvas, area renderer.Rect) {
+	th := i.currentTheme()
 	
 	// Calculate centered position
-	labelWidth := utf8.RuneCountInString(i.Label)
-	inputWidth := 50
+	labelWidth := textwidth.String(i.Label)
+	inputWidth := min(50, area.Width)
 	totalWidth := labelWidth + inputWidth + 10
-	startX := (i.renderer.Width() - totalWidth) / 2
-	startY := i.renderer.Height() / 2 - 2
+	startX := max(area.X+(area.Width-totalWidth)/2, area.X)
+	startY := max(area.Y+area.Height/2-2, area.Y)
 	
 	// Label
-	i.renderer.Print(startX, startY, th.Primary.Sprint(i.Label))
+	c.Print(startX, startY, th.Primary.Sprint(i.Label))
 	
 	// Input box
 	boxY := startY + 1
-	i.renderer.Box(startX, boxY, inputWidth, 3, "")
+	c.StyledBox(renderer.Rect{X: startX, Y: boxY, Width: inputWidth, Height: 3}, "", th.Border.Sequence())
 	
 	// Value or placeholder
 	valueX := startX + 2
 	valueY := boxY + 1
 	
-	displayValue := string(i.buffer)
-	if i.Mask && len(i.buffer) > 0 {
-		displayValue = strings.Repeat("•", len(i.buffer))
-	}
+	displayRunes := i.display()
+	displayValue := string(displayRunes)
 	
 	if len(i.buffer) == 0 && i.Placeholder != "" {
-		i.renderer.Print(valueX, valueY, th.Placeholder.Sprint(i.Placeholder))
+		c.Print(valueX, valueY, th.Placeholder.Sprint(i.Placeholder))
 	} else {
-		i.renderer.Print(valueX, valueY, displayValue)
+		c.Print(valueX, valueY, displayValue)
 	}
 	
 	// Cursor
-	cursorX := valueX + i.cursorPos
-	if i.Mask && i.cursorPos > 0 {
-		cursorX = valueX + i.cursorPos
-	}
-	i.renderer.ShowCursor()
-	i.renderer.MoveCursor(cursorX, valueY)
+	cursorX := valueX + textwidth.String(string(displayRunes[:i.cursorPos]))
+	c.SetCursor(cursorX, valueY)
 	
 	// Error message
+	errorY := boxY + 3
 	if i.error != "" {
-		errorY := boxY + 3
-		i.renderer.Print(startX, errorY, th.Error.Sprint("✗ " + i.error))
+		c.Print(startX, errorY, th.Error.Sprint("✗ " + i.error))
 	}
 	
-	// Help text
-	helpY := i.renderer.Height() - 2
-	helpText := "Enter to confirm • Ctrl+C to cancel"
-	i.renderer.PrintCentered(helpY, th.TextDim.Sprint(helpText))
+	// Help text, when there is room left for it
+	helpY := area.Y + area.Height - 2
+	if helpY > errorY {
+		c.PrintCentered(helpY, help.Bar(i.KeyBindings(), area.Width, th))
+	}
 }
 
 // Validators
 func Required(msg string) func(string) error {
 	return func(value string) error {
 		if strings.TrimSpace(value) == "" {
-			return fmt.Errorf(msg)
+			return errors.New(msg)
 		}
 		return nil
 	}
@@ -0,0 +1,211 @@
+package input_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/vynazevedo/termx/input"
+	"github.com/vynazevedo/termx/renderer"
+	"github.com/vynazevedo/termx/termxtest"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name  string
+		input *input.Input
+		keys  func(*termxtest.Terminal)
+		want  string
+	}{
+		{"type", input.New("Name", nil), func(t *termxtest.Terminal) {
+			t.Type("gopher")
+		}, "gopher"},
+		{"edit in the middle", input.New("Name", nil), func(t *termxtest.Terminal) {
+			t.Type("gpher").Press(termxtest.KeyHome, termxtest.KeyRight).Type("o")
+		}, "gopher"},
+		{"delete", input.New("Name", nil), func(t *termxtest.Terminal) {
+			t.Type("xgopherr").Press(termxtest.KeyBackspace, termxtest.KeyHome, termxtest.KeyDelete)
+		}, "gopher"},
+		{"backspace at start", input.New("Name", nil), func(t *termxtest.Terminal) {
+			t.Type("go").Press(termxtest.KeyHome, termxtest.KeyBackspace, termxtest.KeyLeft, termxtest.KeyEnd).Type("!")
+		}, "go!"},
+		{"paste one line", input.New("Name", nil), func(t *termxtest.Terminal) {
+			t.Type("<").Paste("a\nb\tc").Type(">")
+		}, "<a b c>"},
+		{"max length", input.New("Code", nil).WithMaxLength(4), func(t *termxtest.Terminal) {
+			t.Type("ab").Paste("cdef").Type("g")
+		}, "abcd"},
+		{"wide characters", input.New("City", nil), func(t *termxtest.Terminal) {
+			t.Type("東京").Press(termxtest.KeyLeft).Type("x")
+		}, "東x京"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term := termxtest.NewTerminal(60, 12)
+			tt.keys(term)
+			term.Press(termxtest.KeyEnter)
+
+			var value string
+			tt.input.Value = &value
+			if err := tt.input.WithRenderer(term.Renderer()).Run(); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if value != tt.want {
+				t.Errorf("value = %q, want %q", value, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidator(t *testing.T) {
+	term := termxtest.NewTerminal(60, 12)
+	term.Type("me").Press(termxtest.KeyEnter)
+
+	// The script ends after the rejected Enter, so Run returns io.EOF
+	// with the error still on the screen
+	var value string
+	err := input.New("Email", &value).WithValidator(input.Email()).WithRenderer(term.Renderer()).Run()
+	if err != io.EOF {
+		t.Fatalf("Run() error = %v, want %v", err, io.EOF)
+	}
+	if value != "" {
+		t.Errorf("value = %q after a rejected answer", value)
+	}
+	term.AssertContains(t, "invalid email format")
+}
+
+func TestCursor(t *testing.T) {
+	tests := []struct {
+		name  string
+		keys  string
+		input *input.Input
+		want  string
+	}{
+		{"ascii", "abc\033[D", input.New("L", nil), "ab"},
+		{"wide", "東京\033[D", input.New("L", nil), "東"},
+		{"combining", "éx\033[D", input.New("L", nil), "é"},
+		{"password", "東京\033[D", input.New("L", nil).Password(), "•"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term := termxtest.NewTerminal(60, 12)
+			var d renderer.Decoder
+			d.Feed([]byte(tt.keys))
+			for event, ok := d.Next(); ok; event, ok = d.Next() {
+				tt.input.HandleEvent(event)
+			}
+
+			r := term.Renderer()
+			r.Clear()
+			c := r.Canvas()
+			tt.input.Draw(c, c.Bounds())
+			r.Flush()
+
+			box, y, ok := term.Find("│ ")
+			if !ok {
+				t.Fatal("input box not found")
+			}
+			x, cy := term.Cursor()
+			if want := box + 2 + renderer.StringWidth(tt.want); x != want || cy != y {
+				t.Errorf("cursor at %d,%d, want %d,%d", x, cy, want, y)
+			}
+		})
+	}
+}
+
+func TestPasswordIsMasked(t *testing.T) {
+	term := termxtest.NewTerminal(60, 12)
+	term.Type("s3cret").Press(termxtest.KeyEnter)
+
+	var value string
+	if err := input.New("Password", &value).Password().Inline().WithRenderer(term.Renderer()).Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if value != "s3cret" {
+		t.Errorf("value = %q, want s3cret", value)
+	}
+	term.AssertNotContains(t, "s3cret")
+	term.AssertLine(t, 0, "? Password: ••••••")
+}
+
+func TestValidators(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator func(string) error
+		value     string
+		err       string
+	}{
+		{"required", input.Required("needed"), "  ", "needed"},
+		{"required ok", input.Required("needed"), "x", ""},
+		{"min", input.MinLength(3), "日本", "must be at least 3 characters"},
+		{"min ok", input.MinLength(3), "日本語", ""},
+		{"max", input.MaxLength(2), "日本語", "must be at most 2 characters"},
+		{"max ok", input.MaxLength(3), "日本語", ""},
+		{"email", input.Email(), "gopher", "invalid email format"},
+		{"email ok", input.Email(), "gopher@go.dev", ""},
+	}
+	for _, tt := range tests {
+		err := tt.validator(tt.value)
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != tt.err {
+			t.Errorf("%s: error = %q, want %q", tt.name, got, tt.err)
+		}
+	}
+}
+
+// Without a terminal the value is read from a line of the input
+func TestRunLine(t *testing.T) {
+	tests := []struct {
+		input   string
+		current string
+		want    string
+		err     error
+	}{
+		{"gophr\n", "", "gophr", nil},
+		{"\n", "old", "old", nil},
+		{"toolong\nok\n", "", "ok", nil},
+		{"", "", "", io.EOF},
+	}
+	for _, tt := range tests {
+		in, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, tt.input)
+		w.Close()
+
+		var out bytes.Buffer
+		value := tt.current
+		err = input.New("Name", &value).WithMaxLength(5).WithRenderer(renderer.NewWithIO(in, &out, nil)).Run()
+		in.Close()
+		if err != tt.err || value != tt.want {
+			t.Errorf("input %q: value %q with error %v, want %q with %v", tt.input, value, err, tt.want, tt.err)
+		}
+	}
+}
+
+// Without a terminal the prompt still ends when its context is done, while
+// the piped input never delivers a newline
+func TestRunLineContextDone(t *testing.T) {
+	in, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	defer w.Close()
+	io.WriteString(w, "no newline")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	var value string
+	err = input.New("Name", &value).WithRenderer(renderer.NewWithIO(in, io.Discard, nil)).RunContext(ctx)
+	if err != context.DeadlineExceeded {
+		t.Errorf("RunContext() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,65 @@
+package keymap
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// config is the file format read by Load: a preset to start from and the
+// actions whose chords it replaces, such as
+//
+//	{"preset": "vim", "bindings": {"accept": ["enter", "ctrl+j"]}}
+type config struct {
+	Preset   string              `json:"preset"`
+	Bindings map[string][]string `json:"bindings"`
+}
+
+// Load reads a JSON keymap config from r. The bindings it lists replace the
+// ones of its preset, the default preset if none is named; an empty list
+// unbinds the action. Unknown fields, presets, actions and keys are errors.
+func Load(r io.Reader) (*Keymap, error) {
+	var cfg config
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("keymap: %w", err)
+	}
+
+	k, ok := Preset(cfg.Preset)
+	if !ok {
+		return nil, fmt.Errorf("keymap: unknown preset %q", cfg.Preset)
+	}
+	for name, chords := range cfg.Bindings {
+		action := Action(name)
+		if !known(action) {
+			return nil, fmt.Errorf("keymap: unknown action %q", name)
+		}
+		parsed := make([]Chord, len(chords))
+		for i, chord := range chords {
+			c, err := ParseChord(chord)
+			if err != nil {
+				return nil, err
+			}
+			parsed[i] = c
+		}
+		k.set(action, parsed)
+	}
+	return k, nil
+}
+
+// LoadFile reads a keymap config file as Load does
+func LoadFile(path string) (*Keymap, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	k, err := Load(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return k, nil
+}
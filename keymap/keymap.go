@@ -0,0 +1,287 @@
+// Package keymap maps key chords to named actions, so that the interactive
+// components can be driven with the default keys, the vim or emacs presets
+// or bindings loaded from a config file.
+//
+// Components resolve events with Lookup, asking only for the actions they
+// support, so the same chord may be bound to actions of different
+// components, such as "n" to select.none and confirm.no.
+package keymap
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/vynazevedo/termx/renderer"
+)
+
+// Action names something a component can do in response to a key
+type Action string
+
+// None is returned by Lookup when the event is bound to none of the actions
+const None Action = ""
+
+const (
+	CursorUp       Action = "cursor.up"
+	CursorDown     Action = "cursor.down"
+	CursorLeft     Action = "cursor.left"
+	CursorRight    Action = "cursor.right"
+	CursorHome     Action = "cursor.home"
+	CursorEnd      Action = "cursor.end"
+	PageUp         Action = "page.up"
+	PageDown       Action = "page.down"
+	Accept         Action = "accept"
+	Cancel         Action = "cancel"
+	DeleteBackward Action = "delete.backward"
+	DeleteForward  Action = "delete.forward"
+	FilterStart    Action = "filter.start"
+	FilterClear    Action = "filter.clear"
+	Toggle         Action = "toggle"
+	SelectAll      Action = "select.all"
+	SelectNone     Action = "select.none"
+	Complete       Action = "complete"
+	Yes            Action = "confirm.yes"
+	No             Action = "confirm.no"
+	Help           Action = "help"
+)
+
+// Actions lists every action, in the order they are documented
+var Actions = []Action{
+	CursorUp, CursorDown, CursorLeft, CursorRight, CursorHome, CursorEnd,
+	PageUp, PageDown, Accept, Cancel, DeleteBackward, DeleteForward,
+	FilterStart, FilterClear, Toggle, SelectAll, SelectNone, Complete,
+	Yes, No, Help,
+}
+
+// known reports whether a is one of Actions
+func known(a Action) bool {
+	for _, action := range Actions {
+		if action == a {
+			return true
+		}
+	}
+	return false
+}
+
+// Chord is a key press with its modifiers. Special keys are identified by
+// Key and characters by Rune, with Key left as renderer.KeyUnknown.
+type Chord struct {
+	Key  renderer.Key
+	Rune rune
+	Mod  renderer.Modifier
+}
+
+var keyNames = map[string]renderer.Key{
+	"up":        renderer.KeyArrowUp,
+	"down":      renderer.KeyArrowDown,
+	"left":      renderer.KeyArrowLeft,
+	"right":     renderer.KeyArrowRight,
+	"enter":     renderer.KeyEnter,
+	"return":    renderer.KeyEnter,
+	"tab":       renderer.KeyTab,
+	"backspace": renderer.KeyBackspace,
+	"esc":       renderer.KeyEscape,
+	"escape":    renderer.KeyEscape,
+	"space":     renderer.KeySpace,
+	"home":      renderer.KeyHome,
+	"end":       renderer.KeyEnd,
+	"pgup":      renderer.KeyPageUp,
+	"pageup":    renderer.KeyPageUp,
+	"pgdown":    renderer.KeyPageDown,
+	"pagedown":  renderer.KeyPageDown,
+	"delete":    renderer.KeyDelete,
+	"insert":    renderer.KeyInsert,
+}
+
+// ParseChord parses a chord such as "k", "up", "ctrl+n", "alt+v" or
+// "shift+tab". Names are case-insensitive except for single characters.
+func ParseChord(s string) (Chord, error) {
+	var c Chord
+
+	parts := strings.Split(s, "+")
+	if strings.HasSuffix(s, "++") || s == "+" {
+		// A literal plus as the key
+		parts = append(parts[:len(parts)-2], "+")
+	}
+	name := parts[len(parts)-1]
+	for _, mod := range parts[:len(parts)-1] {
+		switch strings.ToLower(mod) {
+		case "ctrl":
+			c.Mod |= renderer.ModCtrl
+		case "alt":
+			c.Mod |= renderer.ModAlt
+		case "shift":
+			c.Mod |= renderer.ModShift
+		default:
+			return Chord{}, fmt.Errorf("keymap: unknown modifier %q in %q", mod, s)
+		}
+	}
+
+	if utf8.RuneCountInString(name) == 1 {
+		r, _ := utf8.DecodeRuneInString(name)
+		switch {
+		case r == ' ':
+			c.Key = renderer.KeySpace
+		case c.Mod&renderer.ModCtrl != 0:
+			// Terminals send Ctrl with a letter as a control character,
+			// which cannot tell the case apart
+			r = []rune(strings.ToLower(name))[0]
+			switch r {
+			case 'c':
+				return Chord{Key: renderer.KeyCtrlC}, nil
+			case 'd':
+				return Chord{Key: renderer.KeyCtrlD}, nil
+			}
+			c.Rune = r
+		default:
+			c.Rune = r
+		}
+		return c, nil
+	}
+
+	lower := strings.ToLower(name)
+	if key, ok := keyNames[lower]; ok {
+		c.Key = key
+		return c, nil
+	}
+	if n, err := strconv.Atoi(strings.TrimPrefix(lower, "f")); err == nil && lower[0] == 'f' && n >= 1 && n <= 12 {
+		c.Key = renderer.KeyF1 + renderer.Key(n-1)
+		return c, nil
+	}
+	return Chord{}, fmt.Errorf("keymap: unknown key %q in %q", name, s)
+}
+
+// Interrupt is Ctrl+C, which cancels any component whatever the keymap
+var Interrupt = Chord{Key: renderer.KeyCtrlC}
+
+// MustParseChord is like ParseChord but panics on error, for chords written
+// in the source
+func MustParseChord(s string) Chord {
+	c, err := ParseChord(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Matches reports whether event is a press of the chord
+func (c Chord) Matches(event *renderer.InputEvent) bool {
+	if event.Key != c.Key || event.Mod != c.Mod {
+		return false
+	}
+	return c.Key != renderer.KeyUnknown || event.Rune == c.Rune
+}
+
+// Plain reports whether the chord is a plain character, which text entry
+// takes as input rather than as a binding
+func (c Chord) Plain() bool {
+	return c.Mod == 0 && (c.Key == renderer.KeyUnknown || c.Key == renderer.KeySpace)
+}
+
+// String returns the chord the way help text shows it, such as "Ctrl+N"
+// or "↑"
+func (c Chord) String() string {
+	key := c.Key.String()
+	if c.Key == renderer.KeyUnknown {
+		key = string(c.Rune)
+		if c.Mod&(renderer.ModCtrl|renderer.ModAlt) != 0 {
+			key = strings.ToUpper(key)
+		}
+	}
+	if c.Mod == 0 {
+		return key
+	}
+	return c.Mod.String() + "+" + key
+}
+
+// Keymap binds actions to chords. The zero value has no bindings.
+type Keymap struct {
+	name     string
+	modal    bool
+	bindings map[Action][]Chord
+}
+
+// New creates an empty keymap. A modal keymap, like the vim preset, makes
+// components that filter by typing wait for filter.start before they take
+// text, so that plain characters can move the cursor.
+func New(name string, modal bool) *Keymap {
+	return &Keymap{
+		name:     name,
+		modal:    modal,
+		bindings: make(map[Action][]Chord),
+	}
+}
+
+// Name returns the name the keymap was created with
+func (k *Keymap) Name() string {
+	return k.name
+}
+
+// Modal reports whether filtering components wait for filter.start before
+// they take text
+func (k *Keymap) Modal() bool {
+	return k.modal
+}
+
+// Bind replaces the chords bound to action. Chords are written as for
+// ParseChord; Bind panics on one it cannot parse.
+func (k *Keymap) Bind(action Action, chords ...string) *Keymap {
+	parsed := make([]Chord, len(chords))
+	for i, chord := range chords {
+		parsed[i] = MustParseChord(chord)
+	}
+	k.set(action, parsed)
+	return k
+}
+
+func (k *Keymap) set(action Action, chords []Chord) {
+	if k.bindings == nil {
+		k.bindings = make(map[Action][]Chord)
+	}
+	k.bindings[action] = chords
+}
+
+// Keys returns the chords bound to action
+func (k *Keymap) Keys(action Action) []Chord {
+	return k.bindings[action]
+}
+
+// Clone returns a copy of the keymap that can be changed independently
+func (k *Keymap) Clone() *Keymap {
+	c := New(k.name, k.modal)
+	for action, chords := range k.bindings {
+		c.bindings[action] = append([]Chord(nil), chords...)
+	}
+	return c
+}
+
+// Lookup returns the first of actions bound to a chord matching event, or
+// None. With textEntry set, plain characters are left for the component to
+// insert as text and only match special keys and modified chords.
+func (k *Keymap) Lookup(event *renderer.InputEvent, textEntry bool, actions ...Action) Action {
+	for _, action := range actions {
+		for _, chord := range k.bindings[action] {
+			if textEntry && chord.Plain() {
+				continue
+			}
+			if chord.Matches(event) {
+				return action
+			}
+		}
+	}
+	return None
+}
+
+var current = Default()
+
+// Current returns the keymap used by components that were not given one
+// with WithKeymap
+func Current() *Keymap {
+	return current
+}
+
+// Set makes k the keymap used by components that were not given one
+func Set(k *Keymap) {
+	current = k
+}
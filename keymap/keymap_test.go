@@ -0,0 +1,175 @@
+package keymap_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vynazevedo/termx/keymap"
+	"github.com/vynazevedo/termx/renderer"
+)
+
+func TestParseChord(t *testing.T) {
+	tests := []struct {
+		in   string
+		want keymap.Chord
+		str  string
+		err  string
+	}{
+		{"k", keymap.Chord{Rune: 'k'}, "k", ""},
+		{"G", keymap.Chord{Rune: 'G'}, "G", ""},
+		{"?", keymap.Chord{Rune: '?'}, "?", ""},
+		{"+", keymap.Chord{Rune: '+'}, "+", ""},
+		{"alt++", keymap.Chord{Rune: '+', Mod: renderer.ModAlt}, "Alt++", ""},
+		{"space", keymap.Chord{Key: renderer.KeySpace}, "Space", ""},
+		{" ", keymap.Chord{Key: renderer.KeySpace}, "Space", ""},
+		{"Up", keymap.Chord{Key: renderer.KeyArrowUp}, "↑", ""},
+		{"return", keymap.Chord{Key: renderer.KeyEnter}, "Enter", ""},
+		{"pgdown", keymap.Chord{Key: renderer.KeyPageDown}, "PgDn", ""},
+		{"F12", keymap.Chord{Key: renderer.KeyF12}, "F12", ""},
+		{"ctrl+n", keymap.Chord{Rune: 'n', Mod: renderer.ModCtrl}, "Ctrl+N", ""},
+		{"Ctrl+N", keymap.Chord{Rune: 'n', Mod: renderer.ModCtrl}, "Ctrl+N", ""},
+		{"ctrl+c", keymap.Interrupt, "Ctrl+C", ""},
+		{"ctrl+d", keymap.Chord{Key: renderer.KeyCtrlD}, "Ctrl+D", ""},
+		{"alt+v", keymap.Chord{Rune: 'v', Mod: renderer.ModAlt}, "Alt+V", ""},
+		{"shift+tab", keymap.Chord{Key: renderer.KeyTab, Mod: renderer.ModShift}, "Shift+Tab", ""},
+		{"ctrl+alt+left", keymap.Chord{Key: renderer.KeyArrowLeft, Mod: renderer.ModCtrl | renderer.ModAlt}, "Ctrl+Alt+←", ""},
+		{"hyper+k", keymap.Chord{}, "", `keymap: unknown modifier "hyper" in "hyper+k"`},
+		{"f13", keymap.Chord{}, "", `keymap: unknown key "f13" in "f13"`},
+		{"ctrl+", keymap.Chord{}, "", `keymap: unknown key "" in "ctrl+"`},
+	}
+	for _, tt := range tests {
+		got, err := keymap.ParseChord(tt.in)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("ParseChord(%q) error = %v, want %s", tt.in, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseChord(%q) error = %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseChord(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+		if got.String() != tt.str {
+			t.Errorf("ParseChord(%q).String() = %q, want %q", tt.in, got.String(), tt.str)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	char := func(r rune) *renderer.InputEvent { return &renderer.InputEvent{Rune: r} }
+	ctrl := func(r rune) *renderer.InputEvent { return &renderer.InputEvent{Rune: r, Mod: renderer.ModCtrl} }
+	key := func(k renderer.Key) *renderer.InputEvent { return &renderer.InputEvent{Key: k} }
+	list := []keymap.Action{keymap.CursorUp, keymap.CursorDown, keymap.CursorEnd, keymap.PageDown, keymap.Cancel, keymap.FilterClear}
+
+	tests := []struct {
+		preset    string
+		event     *renderer.InputEvent
+		textEntry bool
+		want      keymap.Action
+	}{
+		{"default", key(renderer.KeyArrowDown), false, keymap.CursorDown},
+		{"default", char('j'), false, keymap.None},
+		{"default", key(renderer.KeyEscape), false, keymap.Cancel},
+		{"default", char('c'), false, keymap.FilterClear},
+		{"default", char('c'), true, keymap.None},
+		{"vim", char('j'), false, keymap.CursorDown},
+		{"vim", char('j'), true, keymap.None},
+		{"vim", char('G'), false, keymap.CursorEnd},
+		{"vim", char('g'), false, keymap.None},
+		{"vim", ctrl('f'), true, keymap.PageDown},
+		{"vim", char('q'), false, keymap.Cancel},
+		{"emacs", ctrl('p'), true, keymap.CursorUp},
+		{"emacs", ctrl('g'), false, keymap.Cancel},
+		{"emacs", &renderer.InputEvent{Rune: 'v', Mod: renderer.ModAlt}, true, keymap.None},
+		{"emacs", key(renderer.KeyArrowUp), false, keymap.CursorUp},
+	}
+	for _, tt := range tests {
+		k, ok := keymap.Preset(tt.preset)
+		if !ok {
+			t.Fatalf("Preset(%q) not found", tt.preset)
+		}
+		if got := k.Lookup(tt.event, tt.textEntry, list...); got != tt.want {
+			t.Errorf("%s: Lookup(%+v, textEntry=%v) = %q, want %q", tt.preset, *tt.event, tt.textEntry, got, tt.want)
+		}
+	}
+}
+
+// Lookup returns the first of the actions asked for, so components decide
+// which of two actions sharing a chord wins
+func TestLookupOrder(t *testing.T) {
+	k := keymap.Default()
+	n := &renderer.InputEvent{Rune: 'n'}
+	if got := k.Lookup(n, false, keymap.SelectNone, keymap.No); got != keymap.SelectNone {
+		t.Errorf("Lookup(n, select.none, confirm.no) = %q", got)
+	}
+	if got := k.Lookup(n, false, keymap.No, keymap.SelectNone); got != keymap.No {
+		t.Errorf("Lookup(n, confirm.no, select.none) = %q", got)
+	}
+}
+
+func TestPresetsAreIndependent(t *testing.T) {
+	k, _ := keymap.Preset("VIM")
+	if k.Name() != "vim" || !k.Modal() {
+		t.Errorf("Preset(VIM) = %s, modal %v", k.Name(), k.Modal())
+	}
+	k.Bind(keymap.Accept, "ctrl+j")
+	if other, _ := keymap.Preset("vim"); len(other.Keys(keymap.Accept)) != 1 || other.Keys(keymap.Accept)[0].Key != renderer.KeyEnter {
+		t.Error("changing a preset changed the next copy of it")
+	}
+	clone := k.Clone().Bind(keymap.Accept)
+	if len(k.Keys(keymap.Accept)) != 1 || len(clone.Keys(keymap.Accept)) != 0 {
+		t.Error("changing a clone changed the original keymap")
+	}
+	if _, ok := keymap.Preset("nano"); ok {
+		t.Error("Preset(nano) found")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		config string
+		check  func(*keymap.Keymap) bool
+		err    string
+	}{
+		{`{}`, func(k *keymap.Keymap) bool { return k.Name() == "default" }, ""},
+		{`{"preset": "emacs"}`, func(k *keymap.Keymap) bool { return k.Name() == "emacs" }, ""},
+		{`{"preset": "vim", "bindings": {"accept": ["enter", "ctrl+j"]}}`, func(k *keymap.Keymap) bool {
+			return k.Modal() && k.Lookup(&renderer.InputEvent{Rune: 'j', Mod: renderer.ModCtrl}, true, keymap.Accept) == keymap.Accept
+		}, ""},
+		{`{"bindings": {"select.all": []}}`, func(k *keymap.Keymap) bool { return len(k.Keys(keymap.SelectAll)) == 0 }, ""},
+		{`{"preset": "nano"}`, nil, `keymap: unknown preset "nano"`},
+		{`{"bindings": {"jump": ["j"]}}`, nil, `keymap: unknown action "jump"`},
+		{`{"bindings": {"accept": ["meta+j"]}}`, nil, `keymap: unknown modifier "meta" in "meta+j"`},
+		{`{"keys": {}}`, nil, `keymap: json: unknown field "keys"`},
+		{`{"preset": `, nil, "keymap: unexpected EOF"},
+	}
+	for _, tt := range tests {
+		k, err := keymap.Load(strings.NewReader(tt.config))
+		switch {
+		case tt.err != "":
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("Load(%s) error = %v, want %s", tt.config, err, tt.err)
+			}
+		case err != nil:
+			t.Errorf("Load(%s) error = %v", tt.config, err)
+		case !tt.check(k):
+			t.Errorf("Load(%s) returned the wrong bindings", tt.config)
+		}
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	if err := os.WriteFile(path, []byte(`{"preset": "vim", "bindings": {"jump": []}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := keymap.LoadFile(path)
+	if want := path + `: keymap: unknown action "jump"`; err == nil || err.Error() != want {
+		t.Errorf("LoadFile() error = %v, want %s", err, want)
+	}
+}
@@ -0,0 +1,82 @@
+package keymap
+
+import "strings"
+
+// Default returns the standard bindings: arrows, Enter, Esc and the
+// single-letter shortcuts the components have always used
+func Default() *Keymap {
+	return New("default", false).
+		Bind(CursorUp, "up").
+		Bind(CursorDown, "down").
+		Bind(CursorLeft, "left").
+		Bind(CursorRight, "right").
+		Bind(CursorHome, "home").
+		Bind(CursorEnd, "end").
+		Bind(PageUp, "pgup").
+		Bind(PageDown, "pgdown").
+		Bind(Accept, "enter").
+		Bind(Cancel, "esc").
+		Bind(DeleteBackward, "backspace").
+		Bind(DeleteForward, "delete").
+		Bind(FilterStart, "/").
+		Bind(FilterClear, "esc", "c").
+		Bind(Toggle, "space", "tab").
+		Bind(SelectAll, "a").
+		Bind(SelectNone, "n").
+		Bind(Complete, "tab").
+		Bind(Yes, "y", "Y").
+		Bind(No, "n", "N").
+		Bind(Help, "?", "f1")
+}
+
+// Vim returns the default bindings plus hjkl movement, g/G for the first
+// and last item and Ctrl+B/Ctrl+F for pages. It is modal: components that
+// filter by typing take text only after "/".
+func Vim() *Keymap {
+	k := Default()
+	k.name, k.modal = "vim", true
+	return k.
+		Bind(CursorUp, "up", "k").
+		Bind(CursorDown, "down", "j").
+		Bind(CursorLeft, "left", "h").
+		Bind(CursorRight, "right", "l").
+		Bind(CursorHome, "home", "g").
+		Bind(CursorEnd, "end", "G").
+		Bind(PageUp, "pgup", "ctrl+b").
+		Bind(PageDown, "pgdown", "ctrl+f").
+		Bind(Cancel, "esc", "q").
+		Bind(DeleteForward, "delete", "x")
+}
+
+// Emacs returns the default bindings plus the readline movement and editing
+// chords, with Ctrl+G to cancel
+func Emacs() *Keymap {
+	k := Default()
+	k.name = "emacs"
+	return k.
+		Bind(CursorUp, "up", "ctrl+p").
+		Bind(CursorDown, "down", "ctrl+n").
+		Bind(CursorLeft, "left", "ctrl+b").
+		Bind(CursorRight, "right", "ctrl+f").
+		Bind(CursorHome, "home", "ctrl+a").
+		Bind(CursorEnd, "end", "ctrl+e").
+		Bind(PageUp, "pgup", "alt+v").
+		Bind(PageDown, "pgdown", "ctrl+v").
+		Bind(Cancel, "esc", "ctrl+g").
+		Bind(DeleteForward, "delete", "ctrl+d").
+		Bind(FilterClear, "esc", "c", "ctrl+u")
+}
+
+// Preset returns a fresh copy of the preset with the given name: "default",
+// "vim" or "emacs"
+func Preset(name string) (*Keymap, bool) {
+	switch strings.ToLower(name) {
+	case "default", "":
+		return Default(), true
+	case "vim":
+		return Vim(), true
+	case "emacs":
+		return Emacs(), true
+	}
+	return nil, false
+}
@@ -0,0 +1,92 @@
+package layout
+
+import (
+	"context"
+
+	"github.com/vynazevedo/termx/renderer"
+	"github.com/vynazevedo/termx/widget"
+)
+
+// FocusManager runs several interactive widgets on one screen. Tab and
+// Shift+Tab cycle the focus through the focusable widgets of a group tree,
+// wrapping around at the ends, and a click focuses the widget under the
+// pointer. Every other event is routed to the focused widget and bubbles up
+// through the groups around it when ignored.
+//
+// Tab is taken by the manager, so widgets that use it on their own, such
+// as Confirm, do not see it while managed.
+type FocusManager struct {
+	root     *Group
+	renderer *renderer.Renderer
+}
+
+func NewFocusManager(root *Group) *FocusManager {
+	return &FocusManager{root: root}
+}
+
+// WithRenderer makes Run draw with r instead of its own renderer on the
+// alternate screen
+func (f *FocusManager) WithRenderer(r *renderer.Renderer) *FocusManager {
+	f.renderer = r
+	return f
+}
+
+// Focused returns the widget that has the focus, or nil if none can take it
+func (f *FocusManager) Focused() widget.Widget {
+	return f.root.Focused()
+}
+
+// Focus moves the focus to w. It reports false if w is not in the tree or
+// cannot take the focus.
+func (f *FocusManager) Focus(w widget.Widget) bool {
+	return f.root.Focus(w)
+}
+
+// Run shows the widgets full screen, with the first focusable one focused,
+// until one of them finishes or Ctrl+C goes unhandled
+func (f *FocusManager) Run() error {
+	return f.RunContext(context.Background())
+}
+
+// RunContext is like Run but returns ctx.Err() once ctx is done
+func (f *FocusManager) RunContext(ctx context.Context) error {
+	r := f.renderer
+	if r == nil {
+		r = renderer.New().WithMouse().WithAltScreen()
+	}
+
+	if f.root.focused < 0 {
+		f.root.moveFocus(false)
+	}
+	f.root.SetFocus(true)
+	defer f.root.SetFocus(false)
+
+	return widget.Run(ctx, r, f)
+}
+
+func (f *FocusManager) Focusable() bool {
+	return f.root.Focusable()
+}
+
+func (f *FocusManager) Draw(c *renderer.Canvas, area renderer.Rect) {
+	f.root.Draw(c, area)
+}
+
+// HandleEvent cycles the focus on Tab and Shift+Tab and passes every other
+// event to the group tree
+func (f *FocusManager) HandleEvent(event *renderer.InputEvent) widget.Result {
+	if event.Key == renderer.KeyTab {
+		f.cycle(event.Mod&renderer.ModShift != 0)
+		return widget.Handled
+	}
+	return f.root.HandleEvent(event)
+}
+
+// cycle moves the focus one widget on, wrapping around at the ends
+func (f *FocusManager) cycle(backward bool) {
+	if f.root.moveFocus(backward) {
+		return
+	}
+	f.root.resetFocus()
+	f.root.moveFocus(backward)
+}
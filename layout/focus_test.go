@@ -0,0 +1,169 @@
+package layout_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vynazevedo/termx/layout"
+	"github.com/vynazevedo/termx/renderer"
+	"github.com/vynazevedo/termx/termxtest"
+	"github.com/vynazevedo/termx/widget"
+)
+
+// probe is a widget that records the keys it is given. It handles letters,
+// finishes on Enter and ignores everything else.
+type probe struct {
+	name      string
+	focusable bool
+	focused   bool
+	typed     string
+}
+
+func (p *probe) Draw(c *renderer.Canvas, area renderer.Rect) {
+	text := p.name
+	if p.focused {
+		text = "*" + text
+	}
+	c.Print(area.X, area.Y, text+p.typed)
+}
+
+func (p *probe) HandleEvent(event *renderer.InputEvent) widget.Result {
+	switch {
+	case event.Key == renderer.KeyEnter:
+		return widget.Done(nil)
+	case event.Rune >= 'a' && event.Rune <= 'z':
+		p.typed += string(event.Rune)
+		return widget.Handled
+	}
+	return widget.Ignored
+}
+
+func (p *probe) Focusable() bool       { return p.focusable }
+func (p *probe) SetFocus(focused bool) { p.focused = focused }
+
+// tree returns a manager for
+//
+//	a | pane(b) | (c / d / e) | f
+//
+// where d and f cannot take the focus, and the probes by name
+func tree() (*layout.FocusManager, map[string]*probe) {
+	probes := map[string]*probe{}
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		probes[name] = &probe{name: name, focusable: name != "d" && name != "f"}
+	}
+	nested := layout.NewGroup("vertical").Add(probes["c"]).Add(probes["d"]).Add(probes["e"])
+	root := layout.NewGroup("horizontal").
+		AddFixed(probes["a"], 10).
+		AddFixed(layout.NewPane("B", probes["b"]), 10).
+		AddFixed(nested, 10).
+		AddFixed(probes["f"], 10)
+	return layout.NewFocusManager(root), probes
+}
+
+func TestFocusCycle(t *testing.T) {
+	tab := &renderer.InputEvent{Key: renderer.KeyTab}
+	shiftTab := &renderer.InputEvent{Key: renderer.KeyTab, Mod: renderer.ModShift}
+
+	tests := []struct {
+		name   string
+		events []*renderer.InputEvent
+		want   string
+	}{
+		{"first", []*renderer.InputEvent{tab}, "a"},
+		{"into pane", []*renderer.InputEvent{tab, tab}, "b"},
+		{"into nested group", []*renderer.InputEvent{tab, tab, tab}, "c"},
+		{"skips unfocusable", []*renderer.InputEvent{tab, tab, tab, tab}, "e"},
+		{"wraps forward", []*renderer.InputEvent{tab, tab, tab, tab, tab}, "a"},
+		{"last", []*renderer.InputEvent{shiftTab}, "e"},
+		{"wraps backward", []*renderer.InputEvent{tab, shiftTab}, "e"},
+		{"back out of nested group", []*renderer.InputEvent{shiftTab, shiftTab, shiftTab}, "b"},
+	}
+	for _, tt := range tests {
+		f, probes := tree()
+		for _, event := range tt.events {
+			if res := f.HandleEvent(event); !res.IsHandled() {
+				t.Errorf("%s: Tab was not handled", tt.name)
+			}
+		}
+		if got := f.Focused(); got != probes[tt.want] {
+			t.Errorf("%s: focused %v, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFocus(t *testing.T) {
+	f, probes := tree()
+	if f.Focused() != nil {
+		t.Error("a widget has the focus before any was given it")
+	}
+	if !f.Focus(probes["e"]) || f.Focused() != probes["e"] {
+		t.Error("Focus(e) did not focus the widget in the nested group")
+	}
+	if f.Focus(probes["d"]) || f.Focus(&probe{focusable: true}) {
+		t.Error("Focus accepted an unfocusable widget or one outside the tree")
+	}
+	if f.Focused() != probes["e"] {
+		t.Error("a failed Focus moved the focus")
+	}
+}
+
+func TestKeysBubble(t *testing.T) {
+	var bubbled []string
+	a := &probe{name: "a", focusable: true}
+	inner := layout.NewGroup("vertical").Add(a).OnKey(func(event *renderer.InputEvent) widget.Result {
+		if event.Rune == '1' {
+			bubbled = append(bubbled, "inner")
+			return widget.Handled
+		}
+		return widget.Ignored
+	})
+	root := layout.NewGroup("horizontal").Add(inner).OnKey(func(event *renderer.InputEvent) widget.Result {
+		bubbled = append(bubbled, "root")
+		return widget.Handled
+	})
+	f := layout.NewFocusManager(root)
+	f.Focus(a)
+
+	for _, r := range "x12" {
+		f.HandleEvent(&renderer.InputEvent{Rune: r})
+	}
+	if a.typed != "x" || strings.Join(bubbled, ",") != "inner,root" {
+		t.Errorf("widget typed %q and keys bubbled to %v, want x and [inner root]", a.typed, bubbled)
+	}
+}
+
+func TestOnDone(t *testing.T) {
+	a := &probe{name: "a", focusable: true}
+	var finished widget.Widget
+	root := layout.NewGroup("horizontal").Add(a).OnDone(func(w widget.Widget, err error) widget.Result {
+		finished = w
+		return widget.Handled
+	})
+	f := layout.NewFocusManager(root)
+	f.Focus(a)
+
+	if res := f.HandleEvent(&renderer.InputEvent{Key: renderer.KeyEnter}); res.IsDone() || finished != a {
+		t.Error("OnDone was not called in place of ending the run")
+	}
+}
+
+func TestRunContext(t *testing.T) {
+	term := termxtest.NewTerminal(40, 5)
+	// Click e, type into it, cycle to a, type and cancel
+	term.Click(20, 4).Type("hi").Press(termxtest.KeyTab).Type("yo").Press(termxtest.KeyCtrlC)
+
+	f, probes := tree()
+	err := f.WithRenderer(term.Renderer().WithMouse()).Run()
+	if !errors.Is(err, widget.ErrCancelled) {
+		t.Fatalf("Run() error = %v, want %v", err, widget.ErrCancelled)
+	}
+	if probes["e"].typed != "hi" || probes["a"].typed != "yo" {
+		t.Errorf("e typed %q and a typed %q, want hi and yo", probes["e"].typed, probes["a"].typed)
+	}
+	if probes["a"].focused {
+		t.Error("the focused widget still has the focus after the run")
+	}
+	term.AssertContains(t, "*ayo")
+	term.AssertContains(t, "ehi")
+}
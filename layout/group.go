@@ -0,0 +1,365 @@
+package layout
+
+import (
+	"github.com/vynazevedo/termx/renderer"
+	"github.com/vynazevedo/termx/theme"
+	"github.com/vynazevedo/termx/widget"
+)
+
+// Focuser is implemented by widgets that want to know when they gain or
+// lose the keyboard focus, such as Pane to draw its focus ring
+type Focuser interface {
+	SetFocus(focused bool)
+}
+
+// setFocus tells w about a change of focus, if it wants to know
+func setFocus(w widget.Widget, focused bool) {
+	if f, ok := w.(Focuser); ok {
+		f.SetFocus(focused)
+	}
+}
+
+type groupChild struct {
+	widget widget.Widget
+	size   int // fixed size in cells, or 0 to share the remaining space
+	area   renderer.Rect
+}
+
+// Group lays widgets out side by side ("horizontal") or on top of each other
+// ("vertical") and routes input to the one that has the focus. Groups nest:
+// a group is itself a widget, and keys the focused child ignores bubble up
+// through the handlers set with OnKey on the way to the root.
+type Group struct {
+	direction string
+	children  []*groupChild
+	focused   int
+	active    bool
+	onKey     func(event *renderer.InputEvent) widget.Result
+	onDone    func(w widget.Widget, err error) widget.Result
+}
+
+func NewGroup(direction string) *Group {
+	return &Group{
+		direction: direction,
+		focused:   -1,
+	}
+}
+
+// Add appends w, sharing the space left by fixed-size children equally
+// with the other flexible ones
+func (g *Group) Add(w widget.Widget) *Group {
+	g.children = append(g.children, &groupChild{widget: w})
+	return g
+}
+
+// AddFixed appends w with a fixed size: columns in a horizontal group, rows
+// in a vertical one
+func (g *Group) AddFixed(w widget.Widget, size int) *Group {
+	g.children = append(g.children, &groupChild{widget: w, size: max(size, 0)})
+	return g
+}
+
+// OnKey sets the handler for the keys the focused child ignores. Returning
+// widget.Ignored passes the key on to the parent group.
+func (g *Group) OnKey(handler func(event *renderer.InputEvent) widget.Result) *Group {
+	g.onKey = handler
+	return g
+}
+
+// OnDone sets the handler called when a child finishes, such as an Input on
+// Enter or any widget on Ctrl+C with widget.ErrCancelled. Without one the
+// child's result ends the whole run.
+func (g *Group) OnDone(handler func(w widget.Widget, err error) widget.Result) *Group {
+	g.onDone = handler
+	return g
+}
+
+// Focused returns the widget that has the focus within the group, looking
+// inside panes and nested groups, or nil if there is none
+func (g *Group) Focused() widget.Widget {
+	if g.focused < 0 {
+		return nil
+	}
+	w := unwrap(g.children[g.focused].widget)
+	if inner, ok := w.(*Group); ok {
+		return inner.Focused()
+	}
+	return w
+}
+
+// Focus moves the focus to w, which may be inside a pane or a nested group.
+// It reports false if w is not in the group or cannot take the focus.
+func (g *Group) Focus(w widget.Widget) bool {
+	for i, child := range g.children {
+		if child.widget == w || unwrap(child.widget) == w {
+			if !w.Focusable() {
+				return false
+			}
+			g.focus(i)
+			return true
+		}
+		if inner, ok := container(child.widget); ok && inner.Focus(w) {
+			g.focus(i)
+			return true
+		}
+	}
+	return false
+}
+
+// Focusable reports whether any child takes keyboard input
+func (g *Group) Focusable() bool {
+	for _, child := range g.children {
+		if child.widget.Focusable() {
+			return true
+		}
+	}
+	return false
+}
+
+// SetFocus passes a change of the group's focus on to its focused child
+func (g *Group) SetFocus(focused bool) {
+	g.active = focused
+	if g.focused >= 0 {
+		setFocus(g.children[g.focused].widget, focused)
+	}
+}
+
+// Draw lays the children out in area. Only the focused child may place the
+// cursor.
+func (g *Group) Draw(c *renderer.Canvas, area renderer.Rect) {
+	g.layout(area)
+	for i, child := range g.children {
+		cc := c.Clip(child.area)
+		if !g.active || i != g.focused {
+			cc = cc.WithoutCursor()
+		}
+		if !child.area.Empty() {
+			child.widget.Draw(cc, child.area)
+		}
+	}
+}
+
+// layout divides area between the children
+func (g *Group) layout(area renderer.Rect) {
+	total := area.Height
+	if g.direction == "horizontal" {
+		total = area.Width
+	}
+
+	fixed, flexible := 0, 0
+	for _, child := range g.children {
+		if child.size > 0 {
+			fixed += child.size
+		} else {
+			flexible++
+		}
+	}
+
+	free := max(total-fixed, 0)
+	pos := 0
+	for _, child := range g.children {
+		size := child.size
+		if size == 0 {
+			// Spread the remainder over the first flexible children
+			size = free / flexible
+			if pos < free%flexible {
+				size++
+			}
+			pos++
+		}
+
+		child.area = area
+		if g.direction == "horizontal" {
+			child.area.Width = min(size, area.Width)
+			area.X += child.area.Width
+			area.Width -= child.area.Width
+		} else {
+			child.area.Height = min(size, area.Height)
+			area.Y += child.area.Height
+			area.Height -= child.area.Height
+		}
+	}
+}
+
+// HandleEvent gives mouse events to the child under the pointer, focusing
+// it on a click, and keys to the focused child. Keys the child ignores go
+// to the OnKey handler.
+func (g *Group) HandleEvent(event *renderer.InputEvent) widget.Result {
+	if event.Key == renderer.KeyMouse {
+		return g.handleMouse(event)
+	}
+
+	if g.focused >= 0 {
+		child := g.children[g.focused].widget
+		res := child.HandleEvent(event)
+		if res.IsDone() && g.onDone != nil {
+			return g.onDone(child, res.Err())
+		}
+		if res.IsHandled() {
+			return res
+		}
+	}
+
+	if g.onKey != nil {
+		return g.onKey(event)
+	}
+	return widget.Ignored
+}
+
+func (g *Group) handleMouse(event *renderer.InputEvent) widget.Result {
+	m := event.Mouse
+	for i, child := range g.children {
+		if !child.area.Contains(m.X, m.Y) {
+			continue
+		}
+
+		res := widget.Ignored
+		if m.Button == renderer.MouseLeft && m.Action == renderer.MousePress && child.widget.Focusable() && i != g.focused {
+			g.focus(i)
+			res = widget.Handled
+		}
+
+		if r := child.widget.HandleEvent(event); r.IsDone() && g.onDone != nil {
+			return g.onDone(child.widget, r.Err())
+		} else if r.IsHandled() {
+			return r
+		}
+		return res
+	}
+	return widget.Ignored
+}
+
+// focus gives the focus to the child at index i
+func (g *Group) focus(i int) {
+	if g.focused == i {
+		return
+	}
+	if g.focused >= 0 {
+		setFocus(g.children[g.focused].widget, false)
+	}
+	g.focused = i
+	if inner, ok := container(g.children[i].widget); ok && inner.focused < 0 {
+		inner.moveFocus(false)
+	}
+	if g.active {
+		setFocus(g.children[i].widget, true)
+	}
+}
+
+// moveFocus moves the focus to the next focusable widget, or the previous
+// one when backward is set, entering nested groups. It reports false,
+// leaving the focus alone, when there is none left in that direction.
+func (g *Group) moveFocus(backward bool) bool {
+	if g.focused >= 0 {
+		if inner, ok := container(g.children[g.focused].widget); ok && inner.moveFocus(backward) {
+			return true
+		}
+	}
+
+	step, i := 1, g.focused+1
+	if backward {
+		step, i = -1, g.focused-1
+		if g.focused < 0 {
+			i = len(g.children) - 1
+		}
+	}
+	for ; i >= 0 && i < len(g.children); i += step {
+		w := g.children[i].widget
+		if !w.Focusable() {
+			continue
+		}
+		if inner, ok := container(w); ok {
+			// Enter the nested group from the side we are coming from
+			inner.resetFocus()
+			if !inner.moveFocus(backward) {
+				continue
+			}
+		}
+		g.focus(i)
+		return true
+	}
+	return false
+}
+
+// resetFocus takes the focus away from every child
+func (g *Group) resetFocus() {
+	if g.focused >= 0 {
+		setFocus(g.children[g.focused].widget, false)
+		g.focused = -1
+	}
+}
+
+// container returns the group w is or wraps
+func container(w widget.Widget) (*Group, bool) {
+	g, ok := unwrap(w).(*Group)
+	return g, ok
+}
+
+// unwrap returns the widget inside any panes around w
+func unwrap(w widget.Widget) widget.Widget {
+	for {
+		p, ok := w.(*Pane)
+		if !ok {
+			return w
+		}
+		w = p.widget
+	}
+}
+
+// Pane draws a widget inside a border that turns into a focus ring, in the
+// theme's Focus color, while the widget has the focus
+type Pane struct {
+	title   string
+	widget  widget.Widget
+	focused bool
+	theme   *theme.Theme
+}
+
+func NewPane(title string, w widget.Widget) *Pane {
+	return &Pane{
+		title:  title,
+		widget: w,
+	}
+}
+
+// WithTheme draws the border with t instead of theme.Current()
+func (p *Pane) WithTheme(t *theme.Theme) *Pane {
+	p.theme = t
+	return p
+}
+
+// Widget returns the widget inside the pane
+func (p *Pane) Widget() widget.Widget {
+	return p.widget
+}
+
+func (p *Pane) Focusable() bool {
+	return p.widget.Focusable()
+}
+
+func (p *Pane) SetFocus(focused bool) {
+	p.focused = focused
+	setFocus(p.widget, focused)
+}
+
+func (p *Pane) Draw(c *renderer.Canvas, area renderer.Rect) {
+	th := p.theme
+	if th == nil {
+		th = theme.Current()
+	}
+
+	style := th.Border.Sequence()
+	if p.focused {
+		style = th.Focus.Sequence()
+	}
+	c.StyledBox(area, p.title, style)
+
+	inner := area.Inset(1)
+	if !inner.Empty() {
+		p.widget.Draw(c.Clip(inner), inner)
+	}
+}
+
+func (p *Pane) HandleEvent(event *renderer.InputEvent) widget.Result {
+	return p.widget.HandleEvent(event)
+}
@@ -1,9 +1,10 @@
 package layout
 
 import (
+	"context"
 	"strings"
 	"github.com/vynazevedo/termx/renderer"
-	"golang.org/x/term"
+	"github.com/vynazevedo/termx/widget"
 )
 
 type Layout interface {
@@ -18,15 +19,13 @@ type Split struct {
 	right     Layout
 	width     int
 	height    int
+	renderer  *renderer.Renderer
 }
 
 func NewSplit(direction string) *Split {
-	w, h, _ := term.GetSize(0)
 	return &Split{
 		direction: direction,
 		ratio:     0.5,
-		width:     w,
-		height:    h,
 	}
 }
 
@@ -45,7 +44,99 @@ func (s *Split) SetRight(l Layout) *Split {
 	return s
 }
 
+// WithRenderer makes Run draw with r instead of its own renderer on the
+// alternate screen
+func (s *Split) WithRenderer(r *renderer.Renderer) *Split {
+	s.renderer = r
+	return s
+}
+
+// Run shows the split full screen until Enter, Esc or q is pressed. Unless
+// a renderer was given with WithRenderer it draws on the alternate screen,
+// so the user's scrollback is left intact.
+func (s *Split) Run() error {
+	return s.RunContext(context.Background())
+}
+
+// RunContext is like Run but returns ctx.Err() once ctx is done
+func (s *Split) RunContext(ctx context.Context) error {
+	r := s.renderer
+	if r == nil {
+		r = renderer.New().WithAltScreen()
+	}
+	return widget.Run(ctx, r, s)
+}
+
+// Draw draws both panes and the line between them into area. Panes that
+// implement Draw, such as Box, fill their part of the split; the output of
+// the Render method of other panes is printed at their corner.
+func (s *Split) Draw(c *renderer.Canvas, area renderer.Rect) {
+	s.width, s.height = area.Width, area.Height
+	first, second := area, area
+	
+	if s.direction == "horizontal" {
+		splitCol := int(float64(area.Width) * s.ratio)
+		first.Width = splitCol
+		second.X += splitCol + 1
+		second.Width = max(area.Width-splitCol-1, 0)
+		for i := 0; i < area.Height; i++ {
+			c.Print(area.X+splitCol, area.Y+i, "│")
+		}
+	} else {
+		splitRow := int(float64(area.Height) * s.ratio)
+		first.Height = splitRow
+		second.Y += splitRow + 1
+		second.Height = max(area.Height-splitRow-1, 0)
+		c.Print(area.X, area.Y+splitRow, strings.Repeat("─", area.Width))
+	}
+	
+	if s.left != nil {
+		drawPane(c, s.left, first)
+	}
+	if s.right != nil {
+		drawPane(c, s.right, second)
+	}
+}
+
+// drawPane draws a pane of a split into area
+func drawPane(c *renderer.Canvas, pane Layout, area renderer.Rect) {
+	cc := c.Clip(area)
+	if w, ok := pane.(interface {
+		Draw(c *renderer.Canvas, area renderer.Rect)
+	}); ok {
+		w.Draw(cc, area)
+		return
+	}
+	
+	var out strings.Builder
+	pane.Render(renderer.NewWithIO(strings.NewReader(""), &out, func() (int, int, error) {
+		return area.Width, area.Height, nil
+	}))
+	cc.Print(area.X, area.Y, out.String())
+}
+
+// HandleEvent finishes on Enter, Esc or q and ignores every other event,
+// so the screen is only redrawn when the terminal is resized
+func (s *Split) HandleEvent(event *renderer.InputEvent) widget.Result {
+	switch {
+	case event.Key == renderer.KeyEnter, event.Key == renderer.KeyEscape:
+		return widget.Done(nil)
+	case event.Printable() && (event.Rune == 'q' || event.Rune == 'Q'):
+		return widget.Done(nil)
+	}
+	return widget.Ignored
+}
+
+// Focusable reports that the split takes no keyboard focus of its own
+func (s *Split) Focusable() bool {
+	return false
+}
+
+// Render draws both panes. The geometry is taken from the renderer on every
+// call, so rendering again after a KeyResize event re-lays the split out.
 func (s *Split) Render(r *renderer.Renderer) {
+	s.width, s.height = r.Width(), r.Height()
+	
 	if s.direction == "horizontal" {
 		splitCol := int(float64(s.width) * s.ratio)
 		
@@ -103,17 +194,17 @@ func (b *Box) Render(r *renderer.Renderer) {
 	lines := strings.Split(b.content, "\n")
 	maxWidth := 0
 	for _, line := range lines {
-		if len(line) > maxWidth {
-			maxWidth = len(line)
+		if w := renderer.StringWidth(line); w > maxWidth {
+			maxWidth = w
 		}
 	}
 	
 	if b.border {
-		r.Write("┌─ " + b.title + " " + strings.Repeat("─", maxWidth-len(b.title)-2) + "┐")
+		r.Write("┌─ " + b.title + " " + strings.Repeat("─", max(maxWidth-renderer.StringWidth(b.title)-2, 0)) + "┐")
 		r.NewLine()
 		
 		for _, line := range lines {
-			r.Write("│ " + line + strings.Repeat(" ", maxWidth-len(line)) + " │")
+			r.Write("│ " + renderer.PadRight(line, maxWidth) + " │")
 			r.NewLine()
 		}
 		
@@ -125,4 +216,30 @@ func (b *Box) Render(r *renderer.Renderer) {
 			r.NewLine()
 		}
 	}
-}
\ No newline at end of file
+}
+
+// Draw draws the box filling area, so it can be placed in a Group as a
+// read-only pane. Content that does not fit is cut off.
+func (b *Box) Draw(c *renderer.Canvas, area renderer.Rect) {
+	inner := area
+	if b.border {
+		c.Box(area, b.title)
+		inner = area.Inset(1)
+		inner.X++
+		inner.Width = max(inner.Width-2, 0)
+	}
+	
+	cc := c.Clip(inner)
+	for i, line := range strings.Split(b.content, "\n") {
+		cc.Print(inner.X, inner.Y+i, line)
+	}
+}
+
+// HandleEvent ignores every event: a box only displays its content
+func (b *Box) HandleEvent(event *renderer.InputEvent) widget.Result {
+	return widget.Ignored
+}
+
+func (b *Box) Focusable() bool {
+	return false
+}
@@ -0,0 +1,50 @@
+package layout_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/vynazevedo/termx/layout"
+	"github.com/vynazevedo/termx/renderer"
+	"github.com/vynazevedo/termx/termxtest"
+)
+
+// note is a pane that can only Render, the way panes written before the
+// split drew on a canvas do
+type note struct{ text string }
+
+func (n *note) Render(r *renderer.Renderer) { r.Write(n.text) }
+func (n *note) SetContent(content string)   { n.text = content }
+
+func TestSplitRun(t *testing.T) {
+	box := layout.NewBox("Pods")
+	box.SetContent("api\nworker")
+	split := layout.NewSplit("horizontal").SetLeft(box).SetRight(&note{"logs\nok"})
+
+	var out bytes.Buffer
+	screen := termxtest.NewScreen(30, 6)
+	r := renderer.NewWithIO(strings.NewReader("xyq"), io.MultiWriter(&out, screen), func() (int, int, error) {
+		return 30, 6, nil
+	})
+	if err := split.WithRenderer(r).Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := []string{
+		"┌───┤Pods├────┐│logs",
+		"│ api         ││ok",
+		"│ worker      ││",
+		"│             ││",
+		"│             ││",
+		"└─────────────┘│",
+	}
+	for y, line := range want {
+		screen.AssertLine(t, y, line)
+	}
+	// Keys the split ignores must not redraw the screen
+	if n := strings.Count(out.String(), "\033[2J"); n != 1 {
+		t.Errorf("the screen was cleared %d times, want once", n)
+	}
+}
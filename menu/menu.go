@@ -1,11 +1,16 @@
 package menu
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"strings"
 
+	"github.com/vynazevedo/termx/help"
+	"github.com/vynazevedo/termx/keymap"
 	"github.com/vynazevedo/termx/renderer"
 	"github.com/vynazevedo/termx/theme"
+	"github.com/vynazevedo/termx/textwidth"
+	"github.com/vynazevedo/termx/widget"
 )
 
 // MenuItem represents a single menu item
@@ -35,6 +40,22 @@ type Menu struct {
 	breadcrumb  []string
 	parent      *Menu
 	maxWidth    int
+	rowItems    map[int]int
+	sub         *Menu  // open submenu, which gets the events
+	message     string // error of the last action
+	keymap      *keymap.Keymap
+}
+
+// errBack ends the run of a submenu when the user goes back to its parent
+var errBack = errors.New("back")
+
+// actions are the keymap actions the menu responds to. Item shortcuts take
+// precedence over plain character bindings.
+var actions = []keymap.Action{
+	keymap.CursorUp,
+	keymap.CursorDown,
+	keymap.Accept,
+	keymap.Cancel,
 }
 
 // New creates a new Menu instance
@@ -47,19 +68,54 @@ func New(title string, result *string) *Menu {
 		showIcons:     true,
 		showDesc:      true,
 		showShortcuts: true,
-		theme:         theme.Default(),
-		renderer:      renderer.New(),
+		renderer:      renderer.New().WithMouse().WithAltScreen(),
 		breadcrumb:    make([]string, 0),
 		maxWidth:      80,
 	}
 }
 
-// WithTheme sets the menu theme
+// WithTheme draws the menu with t instead of theme.Current(). Submenus
+// without a theme of their own use their parent's.
 func (m *Menu) WithTheme(t *theme.Theme) *Menu {
 	m.theme = t
 	return m
 }
 
+// currentTheme returns the theme the menu is drawn with
+func (m *Menu) currentTheme() *theme.Theme {
+	switch {
+	case m.theme != nil:
+		return m.theme
+	case m.parent != nil:
+		return m.parent.currentTheme()
+	}
+	return theme.Current()
+}
+
+// WithRenderer sets the renderer used to draw the menu
+func (m *Menu) WithRenderer(r *renderer.Renderer) *Menu {
+	m.renderer = r
+	return m
+}
+
+// WithKeymap resolves keys through k instead of keymap.Current(). Submenus
+// without a keymap of their own use their parent's.
+func (m *Menu) WithKeymap(k *keymap.Keymap) *Menu {
+	m.keymap = k
+	return m
+}
+
+// keys returns the keymap the menu resolves keys through
+func (m *Menu) keys() *keymap.Keymap {
+	switch {
+	case m.keymap != nil:
+		return m.keymap
+	case m.parent != nil:
+		return m.parent.keys()
+	}
+	return keymap.Current()
+}
+
 // WithMaxWidth sets the maximum width for the menu
 func (m *Menu) WithMaxWidth(width int) *Menu {
 	m.maxWidth = width
@@ -148,33 +204,44 @@ func (m *Menu) AddFullItem(item MenuItem) *Menu {
 	return m
 }
 
-// render displays the menu interface
-func (m *Menu) render() {
-	m.renderer.ClearScreen()
+// Draw draws the menu, or the submenu that is open, from the top left
+// corner of area
+func (m *Menu) Draw(c *renderer.Canvas, area renderer.Rect) {
+	if m.sub != nil {
+		m.sub.Draw(c, area)
+		return
+	}
+	
+	th := m.currentTheme()
+	m.rowItems = make(map[int]int)
+	y := area.Y
+	line := func(text string) {
+		c.Print(area.X, y, text)
+		y++
+	}
 	
 	// Breadcrumb
 	if len(m.breadcrumb) > 0 {
 		breadcrumbStr := strings.Join(m.breadcrumb, " > ")
-		fmt.Printf("%s%s%s\n", m.theme.Muted, breadcrumbStr, m.theme.Reset)
+		line(th.Muted.Sprint(breadcrumbStr))
 	}
 	
 	// Title
-	fmt.Printf("%s%s%s\n", m.theme.Primary, m.title, m.theme.Reset)
+	line(th.Primary.Sprint(m.title))
 	
 	// Border
-	titleLen := len(m.title)
+	titleLen := textwidth.String(m.title)
 	if titleLen < m.maxWidth {
 		border := strings.Repeat("═", titleLen)
-		fmt.Printf("%s%s%s\n", m.theme.Secondary, border, m.theme.Reset)
+		line(th.Border.Sprint(border))
 	}
 	
-	fmt.Println()
+	y++
 	
 	// Menu items
 	for i, item := range m.items {
 		if item.Separator {
-			fmt.Printf("%s%s%s\n", m.theme.Muted, 
-				strings.Repeat("─", m.maxWidth/2), m.theme.Reset)
+			line(th.Border.Sprint(strings.Repeat("─", m.maxWidth/2)))
 			continue
 		}
 		
@@ -183,10 +250,12 @@ func (m *Menu) render() {
 			m.moveCursorToNext()
 		}
 		
+		m.rowItems[y] = i
+		
 		// Cursor indicator
 		cursor := "  "
 		if i == m.cursor && !item.Disabled {
-			cursor = m.theme.Primary + "❯ " + m.theme.Reset
+			cursor = th.Cursor.Sprint("❯ ")
 		}
 		
 		// Icon
@@ -202,41 +271,64 @@ func (m *Menu) render() {
 		// Label
 		label := item.Label
 		if item.Disabled {
-			label = m.theme.Muted + label + m.theme.Reset
+			label = th.Disabled.Sprint(label)
 		} else if i == m.cursor {
-			label = m.theme.Highlight + label + m.theme.Reset
+			label = th.Selected.Sprint(label)
 		}
 		
 		// Shortcut
 		shortcut := ""
 		if m.showShortcuts && item.Shortcut != "" {
-			shortcut = fmt.Sprintf(" %s[%s]%s", m.theme.Muted, item.Shortcut, m.theme.Reset)
+			shortcut = " " + th.Muted.Sprintf("[%s]", item.Shortcut)
 		}
 		
 		// Submenu indicator
 		submenuIndicator := ""
 		if item.Submenu != nil {
-			submenuIndicator = fmt.Sprintf(" %s▶%s", m.theme.Secondary, m.theme.Reset)
+			submenuIndicator = " " + th.Secondary.Sprint("▶")
 		}
 		
-		fmt.Printf("%s%s%s%s%s%s\n", cursor, icon, label, shortcut, submenuIndicator, m.theme.Reset)
+		line(cursor + icon + label + shortcut + submenuIndicator + theme.Reset())
 		
 		// Description
 		if m.showDesc && item.Description != "" && !item.Disabled {
-			desc := item.Description
-			if len(desc) > m.maxWidth-6 {
-				desc = desc[:m.maxWidth-9] + "..."
-			}
-			fmt.Printf("    %s%s%s\n", m.theme.Muted, desc, m.theme.Reset)
+			m.rowItems[y] = i
+			desc := textwidth.Truncate(item.Description, m.maxWidth-6, "...")
+			line("    " + th.Muted.Sprint(desc))
 		}
 	}
 	
+	// Error of the last action, shown until the next key
+	if m.message != "" {
+		y++
+		line(th.Error.Sprint(m.message))
+		line("Press any key to continue...")
+		return
+	}
+	
 	// Help text
-	fmt.Printf("\n%sUse ↑↓ para navegar, Enter para selecionar, Esc para voltar/sair%s\n", 
-		m.theme.Muted, m.theme.Reset)
+	y++
+	line(help.Bar(m.KeyBindings(), area.Width, th))
+}
+
+// KeyBindings lists the keys of the open menu, for help
+func (m *Menu) KeyBindings() []help.Binding {
+	if m.sub != nil {
+		return m.sub.KeyBindings()
+	}
 	
+	k := m.keys()
+	cancel := help.Bind(k, keymap.Cancel, "quit").InBar()
 	if m.parent != nil {
-		fmt.Printf("%s← Voltar para menu anterior%s\n", m.theme.Muted, m.theme.Reset)
+		cancel.Help = "back"
+	}
+	return []help.Binding{
+		help.Bind(k, keymap.CursorUp, "up").InBar(),
+		help.Bind(k, keymap.CursorDown, "down").InBar(),
+		help.Bind(k, keymap.Accept, "select").InBar(),
+		cancel,
+		help.Interrupt,
+		help.Bind(k, keymap.Help, "help").InBar(),
 	}
 }
 
@@ -280,115 +372,163 @@ func (m *Menu) moveCursorToPrev() {
 	}
 }
 
+// handleMouse moves the cursor to the clicked item and translates double
+// clicks and wheel movements into the equivalent key event.
+func (m *Menu) handleMouse(mouse renderer.MouseEvent) *renderer.InputEvent {
+	switch mouse.Button {
+	case renderer.MouseWheelUp:
+		return &renderer.InputEvent{Key: renderer.KeyArrowUp}
+	case renderer.MouseWheelDown:
+		return &renderer.InputEvent{Key: renderer.KeyArrowDown}
+	case renderer.MouseLeft:
+		i, ok := m.rowItems[mouse.Y]
+		if mouse.Action != renderer.MousePress || !ok || m.items[i].Disabled {
+			break
+		}
+		m.cursor = i
+		if mouse.Clicks >= 2 {
+			return &renderer.InputEvent{Key: renderer.KeyEnter}
+		}
+	}
+	return &renderer.InputEvent{}
+}
+
 // Run executes the menu interaction
 func (m *Menu) Run() error {
-	defer m.renderer.Close()
+	return m.RunContext(context.Background())
+}
+
+// RunContext is like Run but stops waiting for the user and returns
+// ctx.Err() once ctx is done
+func (m *Menu) RunContext(ctx context.Context) error {
+	m.sub = nil
+	m.message = ""
+	m.skipToFirst()
+	return widget.Run(ctx, m.renderer, help.WithOverlay(m).WithTheme(m.theme))
+}
+
+// Focusable reports that the menu takes keyboard input
+func (m *Menu) Focusable() bool {
+	return true
+}
+
+// HandleEvent moves the cursor, opens submenus and runs actions. It
+// finishes once an item without a submenu is chosen, with the ID stored in
+// the result, or when Esc leaves the menu.
+func (m *Menu) HandleEvent(event *renderer.InputEvent) widget.Result {
+	if m.sub != nil {
+		res := m.sub.HandleEvent(event)
+		if !res.IsDone() {
+			return res
+		}
+		m.sub = nil
+		if err := res.Err(); err != nil && err != errBack {
+			return res
+		}
+		return widget.Handled
+	}
+	
+	if m.message != "" {
+		// Any key dismisses the error of the last action
+		m.message = ""
+		return widget.Handled
+	}
 	
-	// Skip to first valid item
-	if len(m.items) > 0 {
-		for m.cursor < len(m.items) {
-			item := m.items[m.cursor]
-			if !item.Disabled && !item.Separator {
-				break
-			}
-			m.cursor++
+	if event.Key == renderer.KeyMouse {
+		if event = m.handleMouse(event.Mouse); event.Key == renderer.KeyUnknown {
+			return widget.Handled
 		}
 	}
 	
-	for {
-		m.render()
+	shortcut := m.shortcut(event)
+	
+	switch m.keys().Lookup(event, shortcut >= 0, actions...) {
+	case keymap.CursorUp:
+		m.moveCursorToPrev()
+		return widget.Handled
 		
-		key, err := m.renderer.ReadKey()
-		if err != nil {
-			return err
-		}
+	case keymap.CursorDown:
+		m.moveCursorToNext()
+		return widget.Handled
 		
-		switch key {
-		case renderer.KeyUp:
-			m.moveCursorToPrev()
-			
-		case renderer.KeyDown:
-			m.moveCursorToNext()
-			
-		case renderer.KeyEnter:
-			if m.cursor >= len(m.items) {
-				continue
-			}
-			
-			item := m.items[m.cursor]
-			if item.Disabled || item.Separator {
-				continue
-			}
-			
-			// Handle submenu
-			if item.Submenu != nil {
-				item.Submenu.breadcrumb = append(m.breadcrumb, m.title)
-				err := item.Submenu.Run()
-				if err != nil && err.Error() != "voltar" {
-					return err
-				}
-				continue
-			}
-			
-			// Handle action
-			if item.Action != nil {
-				err := item.Action()
-				if err != nil {
-					fmt.Printf("\n%s%s%s\n", m.theme.Error, err.Error(), m.theme.Reset)
-					fmt.Println("Pressione qualquer tecla para continuar...")
-					m.renderer.ReadKey()
-					continue
-				}
-			}
-			
-			// Return selection
-			if m.result != nil {
-				*m.result = item.ID
-			}
-			return nil
-			
-		case renderer.KeyEscape:
-			if m.parent != nil {
-				return fmt.Errorf("voltar")
-			}
-			return fmt.Errorf("operação cancelada")
+	case keymap.Accept:
+		if m.cursor >= len(m.items) {
+			return widget.Handled
 		}
+		return m.choose(m.cursor)
 		
-		// Handle shortcut keys
-		if len(key) == 1 {
-			for i, item := range m.items {
-				if !item.Disabled && !item.Separator && 
-				   strings.ToLower(item.Shortcut) == strings.ToLower(key) {
-					m.cursor = i
-					
-					// Execute the selection
-					if item.Submenu != nil {
-						item.Submenu.breadcrumb = append(m.breadcrumb, m.title)
-						err := item.Submenu.Run()
-						if err != nil && err.Error() != "voltar" {
-							return err
-						}
-						continue
-					}
-					
-					if item.Action != nil {
-						err := item.Action()
-						if err != nil {
-							fmt.Printf("\n%s%s%s\n", m.theme.Error, err.Error(), m.theme.Reset)
-							fmt.Println("Pressione qualquer tecla para continuar...")
-							m.renderer.ReadKey()
-							continue
-						}
-					}
-					
-					if m.result != nil {
-						*m.result = item.ID
-					}
-					return nil
-				}
-			}
+	case keymap.Cancel:
+		if m.parent != nil {
+			return widget.Done(errBack)
+		}
+		return widget.Done(widget.ErrCancelled)
+	}
+	
+	if shortcut >= 0 {
+		m.cursor = shortcut
+		return m.choose(shortcut)
+	}
+	return widget.Ignored
+}
+
+// shortcut returns the index of the item whose shortcut is the key of
+// event, or -1
+func (m *Menu) shortcut(event *renderer.InputEvent) int {
+	if !event.Printable() {
+		return -1
+	}
+	key := string(event.Rune)
+	for i, item := range m.items {
+		if !item.Disabled && !item.Separator && 
+		   strings.ToLower(item.Shortcut) == strings.ToLower(key) {
+			return i
 		}
 	}
+	return -1
+}
+
+// choose opens the submenu of item i or runs its action. A failed action
+// leaves the menu open with the error on screen.
+func (m *Menu) choose(i int) widget.Result {
+	item := m.items[i]
+	if item.Disabled || item.Separator {
+		return widget.Handled
+	}
+	
+	// Handle submenu
+	if item.Submenu != nil {
+		item.Submenu.breadcrumb = append(m.breadcrumb, m.title)
+		item.Submenu.sub = nil
+		item.Submenu.message = ""
+		item.Submenu.skipToFirst()
+		m.sub = item.Submenu
+		return widget.Handled
+	}
+	
+	// Handle action
+	if item.Action != nil {
+		if err := item.Action(); err != nil {
+			m.message = err.Error()
+			return widget.Handled
+		}
+	}
+	
+	// Return selection
+	if m.result != nil {
+		*m.result = item.ID
+	}
+	return widget.Done(nil)
+}
+
+// skipToFirst moves the cursor past disabled items and separators
+func (m *Menu) skipToFirst() {
+	for m.cursor < len(m.items) {
+		item := m.items[m.cursor]
+		if !item.Disabled && !item.Separator {
+			break
+		}
+		m.cursor++
+	}
 }
 
 // Predefined menu configurations
@@ -0,0 +1,55 @@
+package menu_test
+
+import (
+	"testing"
+
+	"github.com/vynazevedo/termx/menu"
+	"github.com/vynazevedo/termx/selector"
+	"github.com/vynazevedo/termx/termxtest"
+)
+
+// A Select opened by a menu action runs on a renderer of its own, as
+// components do by default. It must get the keys typed after it opened and
+// leave the menu's terminal modes alone when it returns.
+func TestActionRunsSelect(t *testing.T) {
+	term := termxtest.NewTerminal(60, 20)
+	term.Press(termxtest.KeyEnter)
+	term.Press(termxtest.KeyDown, termxtest.KeyDown, termxtest.KeyDown, termxtest.KeyEnter)
+
+	var chosen, picked string
+	var modes map[int]bool
+	m := menu.New("Principal", &chosen).
+		WithRenderer(term.Renderer().WithMouse().WithAltScreen()).
+		AddItemWithAction("pick", "Escolher", func() error {
+			sel := selector.New("Letra", []string{"a", "b", "c", "d"}, &picked).
+				WithRenderer(term.NewRenderer().WithMouse())
+			if err := sel.Run(); err != nil {
+				return err
+			}
+			modes = map[int]bool{
+				1000: term.Mode(1000),
+				1006: term.Mode(1006),
+				2004: term.Mode(2004),
+				1049: term.AltScreen(),
+			}
+			return nil
+		})
+	if err := m.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if picked != "d" {
+		t.Errorf("select picked %q, want %q", picked, "d")
+	}
+	if chosen != "pick" {
+		t.Errorf("menu chose %q, want %q", chosen, "pick")
+	}
+	for mode, on := range modes {
+		if !on {
+			t.Errorf("mode %d was turned off for the menu when the select returned", mode)
+		}
+	}
+	if term.AltScreen() || term.Mode(1000) || term.Mode(2004) {
+		t.Error("terminal modes left on after the menu returned")
+	}
+}
@@ -1,11 +1,17 @@
 package multiselect
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/vynazevedo/termx/help"
+	"github.com/vynazevedo/termx/keymap"
 	"github.com/vynazevedo/termx/renderer"
 	"github.com/vynazevedo/termx/theme"
+	"github.com/vynazevedo/termx/widget"
 )
 
 // MultiSelect represents a multi-selection component
@@ -25,8 +31,39 @@ type MultiSelect struct {
 	minSelect   int
 	maxSelect   int
 	showHelp    bool
+	rowOptions  map[int]int
+	message     string // validation error shown until the next key
+	keymap      *keymap.Keymap
 }
 
+// actions are the keymap actions the list responds to
+var actions = []keymap.Action{
+	keymap.CursorUp,
+	keymap.CursorDown,
+	keymap.CursorHome,
+	keymap.CursorEnd,
+	keymap.PageUp,
+	keymap.PageDown,
+	keymap.Toggle,
+	keymap.Accept,
+	keymap.Cancel,
+	keymap.FilterStart,
+	keymap.FilterClear,
+	keymap.SelectAll,
+	keymap.SelectNone,
+}
+
+// searchActions are the keymap actions the search bar responds to
+var searchActions = []keymap.Action{
+	keymap.Accept,
+	keymap.Cancel,
+	keymap.FilterClear,
+	keymap.DeleteBackward,
+}
+
+// maxDisplay is the number of options shown at once
+const maxDisplay = 10
+
 // New creates a new MultiSelect instance
 func New(label string, options []string, result *[]string) *MultiSelect {
 	return &MultiSelect{
@@ -37,8 +74,7 @@ func New(label string, options []string, result *[]string) *MultiSelect {
 		cursor:     0,
 		searchMode: false,
 		filtered:   make([]int, len(options)),
-		theme:      theme.Default,
-		renderer:   renderer.New(),
+		renderer:   renderer.New().WithMouse(),
 		minSelect:  0,
 		maxSelect:  len(options),
 		showHelp:   true,
@@ -57,12 +93,40 @@ func (ms *MultiSelect) WithValidator(validator func([]string) error) *MultiSelec
 	return ms
 }
 
-// WithTheme sets the theme
+// WithTheme draws the list with t instead of theme.Current()
 func (ms *MultiSelect) WithTheme(t *theme.Theme) *MultiSelect {
 	ms.theme = t
 	return ms
 }
 
+// currentTheme returns the theme the list is drawn with
+func (ms *MultiSelect) currentTheme() *theme.Theme {
+	if ms.theme != nil {
+		return ms.theme
+	}
+	return theme.Current()
+}
+
+// WithRenderer sets the renderer used to draw the list
+func (ms *MultiSelect) WithRenderer(r *renderer.Renderer) *MultiSelect {
+	ms.renderer = r
+	return ms
+}
+
+// WithKeymap resolves keys through k instead of keymap.Current()
+func (ms *MultiSelect) WithKeymap(k *keymap.Keymap) *MultiSelect {
+	ms.keymap = k
+	return ms
+}
+
+// keys returns the keymap the list resolves keys through
+func (ms *MultiSelect) keys() *keymap.Keymap {
+	if ms.keymap != nil {
+		return ms.keymap
+	}
+	return keymap.Current()
+}
+
 // WithMinSelect sets minimum number of selections required
 func (ms *MultiSelect) WithMinSelect(min int) *MultiSelect {
 	ms.minSelect = min
@@ -83,8 +147,9 @@ func (ms *MultiSelect) WithoutHelp() *MultiSelect {
 
 // initFiltered initializes the filtered options list
 func (ms *MultiSelect) initFiltered() {
+	ms.filtered = ms.filtered[:0]
 	for i := range ms.options {
-		ms.filtered[i] = i
+		ms.filtered = append(ms.filtered, i)
 	}
 }
 
@@ -105,44 +170,52 @@ func (ms *MultiSelect) filterOptions() {
 	}
 }
 
-// render displays the multi-select interface
-func (ms *MultiSelect) render() {
-	ms.renderer.ClearScreen()
+// Draw draws the label, the search bar and the visible options from the
+// top left corner of area
+func (ms *MultiSelect) Draw(c *renderer.Canvas, area renderer.Rect) {
+	th := ms.currentTheme()
+	y := area.Y
+	line := func(text string) {
+		c.Print(area.X, y, text)
+		y++
+	}
 	
 	// Header
-	fmt.Printf("%s\n", ms.theme.Primary.Sprint(ms.label))
+	line(th.Primary.Sprint(ms.label))
 	
 	if ms.showHelp {
-		helpText := "Use ↑↓ para navegar, Space para selecionar, / para buscar, Enter para confirmar, Esc para cancelar"
-		fmt.Printf("%s\n", ms.theme.Muted.Sprint(helpText))
+		line(help.Bar(ms.KeyBindings(), area.Width, th))
 	}
 	
 	// Search bar
 	if ms.searchMode {
-		fmt.Printf("\nBuscar: %s\n", ms.theme.Primary.Sprint(ms.searchTerm))
+		y++
+		line("Search: " + th.Primary.Sprint(ms.searchTerm))
 	} else if ms.searchTerm != "" {
-		fmt.Printf("\n%s\n", ms.theme.Muted.Sprint(fmt.Sprintf("Buscar: %s (pressione / para editar)", ms.searchTerm)))
+		y++
+		line(th.Muted.Sprint(fmt.Sprintf("Search: %s (press / to edit)", ms.searchTerm)))
 	}
 	
 	// Selection count
 	selectedCount := len(ms.getSelectedValues())
-	fmt.Printf("\n%s\n", ms.theme.Secondary.Sprint(fmt.Sprintf("Selecionados: %d/%d", selectedCount, len(ms.options))))
+	y++
+	line(th.Secondary.Sprint(fmt.Sprintf("Selected: %d/%d", selectedCount, len(ms.options))))
 	
 	if ms.placeholder != "" && selectedCount == 0 {
-		fmt.Printf("%s\n", ms.theme.Muted.Sprint(ms.placeholder))
+		line(th.Muted.Sprint(ms.placeholder))
 	}
 	
-	fmt.Println()
+	y++
 	
 	// Options list
+	ms.rowOptions = make(map[int]int)
 	visibleOptions := ms.filtered
 	if len(visibleOptions) == 0 {
-		fmt.Printf("%s\n", ms.theme.Error.Sprint("Nenhuma opção encontrada"))
+		line(th.Error.Sprint("No options found"))
 		return
 	}
 	
 	// Calculate display window
-	maxDisplay := 10
 	start := 0
 	if ms.cursor >= maxDisplay {
 		start = ms.cursor - maxDisplay + 1
@@ -155,31 +228,99 @@ func (ms *MultiSelect) render() {
 	for i := start; i < end; i++ {
 		optionIndex := visibleOptions[i]
 		option := ms.options[optionIndex]
+		ms.rowOptions[y] = i
 		
 		// Cursor indicator
 		cursor := "  "
 		if i == ms.cursor {
-			cursor = ms.theme.Primary.Sprint("❯ ")
+			cursor = th.Cursor.Sprint("❯ ")
 		}
 		
 		// Selection indicator
 		checkbox := "☐"
 		if ms.selected[optionIndex] {
-			checkbox = ms.theme.Success.Sprint("☑")
+			checkbox = th.Success.Sprint("☑")
 		}
 		
-		// Highlight current option
+		// Highlight current option and the part matching the search
+		base := theme.Style{}
 		if i == ms.cursor {
-			option = ms.theme.Selected.Sprint(option)
+			base = th.Selected
 		}
+		option = theme.Highlight(option, ms.searchTerm, base, th.Match)
 		
-		fmt.Printf("%s%s %s\n", cursor, checkbox, option)
+		line(cursor + checkbox + " " + option)
 	}
 	
 	// Show more indicator
 	if end < len(visibleOptions) {
-		fmt.Printf("%s\n", ms.theme.Muted.Sprint(fmt.Sprintf("... e mais %d opções", len(visibleOptions)-end)))
+		line(th.Muted.Sprint(fmt.Sprintf("... and %d more options", len(visibleOptions)-end)))
+	}
+	
+	// Validation error, shown until the next key
+	if ms.message != "" {
+		y++
+		line(th.Error.Sprint(ms.message))
+		line("Press any key to continue...")
+	}
+}
+
+// KeyBindings lists the keys of the list, or of the search bar while it is
+// active, for help
+func (ms *MultiSelect) KeyBindings() []help.Binding {
+	k := ms.keys()
+	if ms.searchMode {
+		bindings := []help.Binding{
+			help.Bind(k, keymap.Accept, "apply search").InBar(),
+			help.Bind(k, keymap.Cancel, "leave search").InBar(),
+			help.Bind(k, keymap.FilterClear, "clear search").InBar(),
+			help.Bind(k, keymap.DeleteBackward, "delete from search"),
+			help.Interrupt.InBar(),
+			help.Bind(k, keymap.Help, "help").InBar(),
+		}
+		for n := range bindings {
+			bindings[n] = bindings[n].TextEntry()
+		}
+		return bindings
+	}
+	return []help.Binding{
+		help.Bind(k, keymap.CursorUp, "up").InBar(),
+		help.Bind(k, keymap.CursorDown, "down").InBar(),
+		help.Bind(k, keymap.Toggle, "toggle").InBar(),
+		help.Bind(k, keymap.FilterStart, "search").InBar(),
+		help.Bind(k, keymap.Accept, "confirm").InBar(),
+		help.Bind(k, keymap.Cancel, "cancel").InBar(),
+		help.Bind(k, keymap.CursorHome, "first option"),
+		help.Bind(k, keymap.CursorEnd, "last option"),
+		help.Bind(k, keymap.PageUp, "page up"),
+		help.Bind(k, keymap.PageDown, "page down"),
+		help.Bind(k, keymap.SelectAll, "select all"),
+		help.Bind(k, keymap.SelectNone, "select none"),
+		help.Bind(k, keymap.FilterClear, "clear search"),
+		help.Interrupt,
+		help.Bind(k, keymap.Help, "help").InBar(),
+	}
+}
+
+// handleMouse moves the cursor to the clicked option and translates double
+// clicks and wheel movements into the equivalent key event.
+func (ms *MultiSelect) handleMouse(m renderer.MouseEvent) *renderer.InputEvent {
+	switch m.Button {
+	case renderer.MouseWheelUp:
+		return &renderer.InputEvent{Key: renderer.KeyArrowUp}
+	case renderer.MouseWheelDown:
+		return &renderer.InputEvent{Key: renderer.KeyArrowDown}
+	case renderer.MouseLeft:
+		i, ok := ms.rowOptions[m.Y]
+		if m.Action != renderer.MousePress || !ok {
+			break
+		}
+		ms.cursor = i
+		if m.Clicks >= 2 {
+			return &renderer.InputEvent{Key: renderer.KeyEnter}
+		}
 	}
+	return &renderer.InputEvent{}
 }
 
 // getSelectedValues returns the currently selected values
@@ -199,12 +340,12 @@ func (ms *MultiSelect) validateSelection() error {
 	
 	// Check minimum selections
 	if len(selected) < ms.minSelect {
-		return fmt.Errorf("pelo menos %d opções devem ser selecionadas", ms.minSelect)
+		return fmt.Errorf("select at least %d options", ms.minSelect)
 	}
 	
 	// Check maximum selections
 	if len(selected) > ms.maxSelect {
-		return fmt.Errorf("no máximo %d opções podem ser selecionadas", ms.maxSelect)
+		return fmt.Errorf("select at most %d options", ms.maxSelect)
 	}
 	
 	// Custom validation
@@ -217,99 +358,205 @@ func (ms *MultiSelect) validateSelection() error {
 
 // Run executes the multi-select interaction
 func (ms *MultiSelect) Run() error {
-	defer ms.renderer.Close()
-	
+	return ms.RunContext(context.Background())
+}
+
+// RunContext is like Run but stops waiting for the user and returns
+// ctx.Err() once ctx is done
+func (ms *MultiSelect) RunContext(ctx context.Context) error {
 	ms.initFiltered()
 	ms.filterOptions()
+	ms.message = ""
+	
+	err := widget.Run(ctx, ms.renderer, help.WithOverlay(ms).WithTheme(ms.theme))
+	if errors.Is(err, renderer.ErrNotTerminal) {
+		return ms.runLine(ctx)
+	}
+	return err
+}
+
+// Focusable reports that the list takes keyboard input
+func (ms *MultiSelect) Focusable() bool {
+	return true
+}
+
+// HandleEvent moves the cursor, toggles options and edits the search. It
+// finishes on Enter once the selection is valid, storing it in the result,
+// or on Esc.
+func (ms *MultiSelect) HandleEvent(event *renderer.InputEvent) widget.Result {
+	if ms.message != "" {
+		// Any key dismisses the validation error
+		ms.message = ""
+		return widget.Handled
+	}
+	
+	if event.Key == renderer.KeyMouse {
+		if ms.searchMode {
+			return widget.Ignored
+		}
+		if event = ms.handleMouse(event.Mouse); event.Key == renderer.KeyUnknown {
+			return widget.Handled
+		}
+	}
+	
+	if ms.searchMode {
+		return ms.handleSearch(event)
+	}
+	
+	switch ms.keys().Lookup(event, false, actions...) {
+	case keymap.CursorUp:
+		if ms.cursor > 0 {
+			ms.cursor--
+		}
+	case keymap.CursorDown:
+		if ms.cursor < len(ms.filtered)-1 {
+			ms.cursor++
+		}
+	case keymap.CursorHome:
+		ms.cursor = 0
+	case keymap.CursorEnd:
+		ms.cursor = max(len(ms.filtered)-1, 0)
+	case keymap.PageUp:
+		ms.cursor = max(ms.cursor-maxDisplay, 0)
+	case keymap.PageDown:
+		ms.cursor = max(min(ms.cursor+maxDisplay, len(ms.filtered)-1), 0)
+	case keymap.Toggle:
+		if len(ms.filtered) > 0 {
+			optionIndex := ms.filtered[ms.cursor]
+			if ms.selected[optionIndex] {
+				delete(ms.selected, optionIndex)
+			} else {
+				// Check if we can select more
+				if len(ms.getSelectedValues()) < ms.maxSelect {
+					ms.selected[optionIndex] = true
+				}
+			}
+		}
+	case keymap.Accept:
+		if err := ms.validateSelection(); err != nil {
+			ms.message = err.Error()
+			return widget.Handled
+		}
+		
+		selected := ms.getSelectedValues()
+		*ms.result = selected
+		return widget.Done(nil)
+	case keymap.Cancel:
+		return widget.Done(widget.ErrCancelled)
+	case keymap.FilterStart:
+		ms.searchMode = true
+	case keymap.FilterClear:
+		if len(ms.filtered) > 0 {
+			ms.searchTerm = ""
+			ms.filterOptions()
+			ms.cursor = 0
+		}
+	case keymap.SelectAll:
+		// Select all visible options
+		for _, optionIndex := range ms.filtered {
+			if len(ms.getSelectedValues()) < ms.maxSelect {
+				ms.selected[optionIndex] = true
+			} else {
+				break
+			}
+		}
+	case keymap.SelectNone:
+		// Deselect all
+		ms.selected = make(map[int]bool)
+	default:
+		if event.Key != renderer.KeyPaste {
+			return widget.Ignored
+		}
+		ms.searchMode = true
+		ms.searchTerm += renderer.SingleLine(event.Text)
+		ms.filterOptions()
+		ms.cursor = 0
+	}
+	return widget.Handled
+}
+
+// handleSearch edits the search term while the search bar is active
+func (ms *MultiSelect) handleSearch(event *renderer.InputEvent) widget.Result {
+	switch ms.keys().Lookup(event, true, searchActions...) {
+	case keymap.Cancel:
+		ms.searchMode = false
+	case keymap.Accept:
+		ms.searchMode = false
+		ms.filterOptions()
+		ms.cursor = 0
+	case keymap.FilterClear:
+		ms.searchTerm = ""
+		ms.filterOptions()
+		ms.cursor = 0
+	case keymap.DeleteBackward:
+		if len(ms.searchTerm) > 0 {
+			term := []rune(ms.searchTerm)
+			ms.searchTerm = string(term[:len(term)-1])
+			ms.filterOptions()
+			ms.cursor = 0
+		}
+	default:
+		switch {
+		case event.Key == renderer.KeyPaste:
+			ms.searchTerm += renderer.SingleLine(event.Text)
+		case event.Printable():
+			ms.searchTerm += string(event.Rune)
+		default:
+			return widget.Ignored
+		}
+		ms.filterOptions()
+		ms.cursor = 0
+	}
+	return widget.Handled
+}
+
+// runLine lists the options with numbers when there is no terminal and
+// reads the selection from a plain line of comma or space separated numbers
+func (ms *MultiSelect) runLine(ctx context.Context) error {
+	th := ms.currentTheme()
+	
+	ms.renderer.Write(th.Primary.Sprint(ms.label) + "\n")
+	for i, option := range ms.options {
+		ms.renderer.Write(fmt.Sprintf("  %d) %s\n", i+1, option))
+	}
 	
 	for {
-		ms.render()
+		ms.renderer.Write("Enter numbers separated by commas: ")
 		
-		key, err := ms.renderer.ReadKey()
+		answer, err := ms.renderer.ReadLineContext(ctx)
 		if err != nil {
 			return err
 		}
 		
-		if ms.searchMode {
-			switch key {
-			case renderer.KeyEscape:
-				ms.searchMode = false
-			case renderer.KeyEnter:
-				ms.searchMode = false
-				ms.filterOptions()
-				ms.cursor = 0
-			case renderer.KeyBackspace:
-				if len(ms.searchTerm) > 0 {
-					ms.searchTerm = ms.searchTerm[:len(ms.searchTerm)-1]
-					ms.filterOptions()
-					ms.cursor = 0
-				}
-			default:
-				if len(key) == 1 && key[0] >= 32 && key[0] <= 126 {
-					ms.searchTerm += key
-					ms.filterOptions()
-					ms.cursor = 0
-				}
-			}
+		selected, err := ms.parseNumbers(answer)
+		if err == nil {
+			ms.selected = selected
+			err = ms.validateSelection()
+		}
+		if err != nil {
+			ms.renderer.Write(th.Error.Sprint(err.Error()) + "\n")
 			continue
 		}
 		
-		switch key {
-		case renderer.KeyUp:
-			if ms.cursor > 0 {
-				ms.cursor--
-			}
-		case renderer.KeyDown:
-			if ms.cursor < len(ms.filtered)-1 {
-				ms.cursor++
-			}
-		case renderer.KeySpace:
-			if len(ms.filtered) > 0 {
-				optionIndex := ms.filtered[ms.cursor]
-				if ms.selected[optionIndex] {
-					delete(ms.selected, optionIndex)
-				} else {
-					// Check if we can select more
-					if len(ms.getSelectedValues()) < ms.maxSelect {
-						ms.selected[optionIndex] = true
-					}
-				}
-			}
-		case "/":
-			ms.searchMode = true
-		case "c":
-			if len(ms.filtered) > 0 {
-				ms.searchTerm = ""
-				ms.filterOptions()
-				ms.cursor = 0
-			}
-		case "a":
-			// Select all visible options
-			for _, optionIndex := range ms.filtered {
-				if len(ms.getSelectedValues()) < ms.maxSelect {
-					ms.selected[optionIndex] = true
-				} else {
-					break
-				}
-			}
-		case "n":
-			// Deselect all
-			ms.selected = make(map[int]bool)
-		case renderer.KeyEnter:
-			if err := ms.validateSelection(); err != nil {
-				fmt.Printf("\n%s%s%s\n", ms.theme.Error, err.Error(), ms.theme.Reset)
-				fmt.Println("Pressione qualquer tecla para continuar...")
-				ms.renderer.ReadKey()
-				continue
-			}
-			
-			selected := ms.getSelectedValues()
-			*ms.result = selected
-			return nil
-		case renderer.KeyEscape:
-			return fmt.Errorf("operação cancelada")
+		*ms.result = ms.getSelectedValues()
+		return nil
+	}
+}
+
+// parseNumbers converts a line such as "1, 3 4" into selected option indexes
+func (ms *MultiSelect) parseNumbers(answer string) (map[int]bool, error) {
+	selected := make(map[int]bool)
+	fields := strings.FieldsFunc(answer, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	for _, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 1 || n > len(ms.options) {
+			return nil, fmt.Errorf("invalid option: %s", field)
 		}
+		selected[n-1] = true
 	}
+	return selected, nil
 }
 
 // Predefined multi-select configurations
@@ -320,24 +567,24 @@ func Technologies(result *[]string) *MultiSelect {
 		"Docker", "Kubernetes", "AWS", "GCP", "Azure",
 		"PostgreSQL", "MySQL", "MongoDB", "Redis", "Elasticsearch",
 	}
-	return New("Selecione as tecnologias:", options, result).
-		WithPlaceholder("Nenhuma tecnologia selecionada").
+	return New("Select technologies:", options, result).
+		WithPlaceholder("No technologies selected").
 		WithMinSelect(1).
 		WithMaxSelect(5)
 }
 
 func Environments(result *[]string) *MultiSelect {
 	options := []string{"development", "staging", "production", "testing"}
-	return New("Selecione os ambientes:", options, result).
+	return New("Select environments:", options, result).
 		WithMinSelect(1)
 }
 
 func Features(result *[]string) *MultiSelect {
 	options := []string{
-		"Autenticação", "Autorização", "Cache", "Logging",
-		"Monitoramento", "Métricas", "Backup", "Recuperação",
+		"Authentication", "Authorization", "Cache", "Logging",
+		"Monitoring", "Metrics", "Backup", "Recovery",
 		"API REST", "GraphQL", "WebSocket", "gRPC",
 	}
-	return New("Selecione os recursos:", options, result).
-		WithPlaceholder("Nenhum recurso selecionado")
+	return New("Select features:", options, result).
+		WithPlaceholder("No features selected")
 }
\ No newline at end of file
@@ -0,0 +1,103 @@
+package multiselect_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vynazevedo/termx/multiselect"
+	"github.com/vynazevedo/termx/renderer"
+	"github.com/vynazevedo/termx/termxtest"
+	"github.com/vynazevedo/termx/widget"
+)
+
+var langs = []string{"Go", "Rust", "Python", "TypeScript", "Java"}
+
+func TestRun(t *testing.T) {
+	down, space, enter := termxtest.KeyDown, termxtest.KeySpace, termxtest.KeyEnter
+	tests := []struct {
+		name     string
+		min, max int
+		keys     []string
+		want     []string
+	}{
+		{"nothing", 0, 5, []string{enter}, nil},
+		{"toggle", 0, 5, []string{space, down, down, space, enter}, []string{"Go", "Python"}},
+		{"toggle twice", 0, 5, []string{space, space, down, space, enter}, []string{"Rust"}},
+		{"all", 0, 5, []string{"a", enter}, langs},
+		{"all up to max", 0, 2, []string{"a", enter}, []string{"Go", "Rust"}},
+		{"none", 0, 5, []string{"a", "n", enter}, nil},
+		{"max", 0, 1, []string{space, down, space, enter}, []string{"Go"}},
+		{"min", 1, 5, []string{enter, "x", space, enter}, []string{"Go"}},
+		{"search", 0, 5, []string{"/", "t", "h", enter, space, enter}, []string{"Python"}},
+		{"search then clear", 0, 5, []string{"/", "j", "a", enter, "c", space, enter}, []string{"Go"}},
+		{"paste searches", 0, 5, []string{"\033[200~scr\033[201~", enter, space, enter}, []string{"TypeScript"}},
+		{"end", 0, 5, []string{termxtest.KeyEnd, space, enter}, []string{"Java"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term := termxtest.NewTerminal(60, 20)
+			term.Press(tt.keys...)
+
+			var result []string
+			err := multiselect.New("Languages", langs, &result).
+				WithMinSelect(tt.min).WithMaxSelect(tt.max).
+				WithRenderer(term.Renderer()).Run()
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.want) {
+				t.Errorf("result = %q, want %q", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestCancel(t *testing.T) {
+	term := termxtest.NewTerminal(60, 20)
+	term.Press(termxtest.KeySpace, termxtest.KeyEscape)
+
+	result := []string{"unchanged"}
+	err := multiselect.New("Languages", langs, &result).WithRenderer(term.Renderer()).Run()
+	if err != widget.ErrCancelled || len(result) != 1 {
+		t.Errorf("Run() = %q, %v, want %v and the result untouched", result, err, widget.ErrCancelled)
+	}
+}
+
+// Without a terminal the selection is read as a line of option numbers
+func TestRunLine(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+		err   error
+	}{
+		{"1, 3\n", []string{"Go", "Python"}, nil},
+		{"5 2\t4\n", []string{"Rust", "TypeScript", "Java"}, nil},
+		{"6\nx\n2\n", []string{"Rust"}, nil},
+		{"\n1\n", []string{"Go"}, nil},
+		{"", nil, io.EOF},
+	}
+	for _, tt := range tests {
+		in, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, tt.input)
+		w.Close()
+
+		var out bytes.Buffer
+		var result []string
+		err = multiselect.New("Languages", langs, &result).WithMinSelect(1).
+			WithRenderer(renderer.NewWithIO(in, &out, nil)).Run()
+		in.Close()
+		if err != tt.err || !reflect.DeepEqual(result, tt.want) {
+			t.Errorf("input %q: result %q with error %v, want %q with %v", tt.input, result, err, tt.want, tt.err)
+		}
+		if !strings.Contains(out.String(), "Enter numbers separated by commas: ") {
+			t.Errorf("input %q: output %q does not ask for the option numbers", tt.input, out.String())
+		}
+	}
+}
@@ -16,6 +16,8 @@ type Bar struct {
 	showPercent bool
 	char     string
 	emptyChar string
+	renderer *renderer.Renderer
+	theme    *theme.Theme
 }
 
 func NewBar(total int) *Bar {
@@ -45,6 +47,26 @@ func (b *Bar) WithChar(char, empty string) *Bar {
 	return b
 }
 
+// WithRenderer draws the bar with r instead of a renderer on the standard streams
+func (b *Bar) WithRenderer(r *renderer.Renderer) *Bar {
+	b.renderer = r
+	return b
+}
+
+// WithTheme draws the bar with t instead of theme.Current()
+func (b *Bar) WithTheme(t *theme.Theme) *Bar {
+	b.theme = t
+	return b
+}
+
+// currentTheme returns the theme the bar is drawn with
+func (b *Bar) currentTheme() *theme.Theme {
+	if b.theme != nil {
+		return b.theme
+	}
+	return theme.Current()
+}
+
 func (b *Bar) Update(current int) {
 	b.current = current
 	b.Render()
@@ -56,7 +78,10 @@ func (b *Bar) Increment() {
 }
 
 func (b *Bar) Render() {
-	r := renderer.New()
+	r := b.renderer
+	if r == nil {
+		r = renderer.New()
+	}
 	defer r.Close()
 	
 	percent := float64(b.current) / float64(b.total)
@@ -70,7 +95,7 @@ func (b *Bar) Render() {
 	}
 	
 	r.Write("[")
-	r.WriteStyled(strings.Repeat(b.char, filled), theme.Current().Success.Foreground)
+	r.WriteStyled(strings.Repeat(b.char, filled), b.currentTheme().Success.Sequence())
 	r.Write(strings.Repeat(b.emptyChar, b.width-filled))
 	r.Write("]")
 	
@@ -87,6 +112,8 @@ type Spinner struct {
 	delay   time.Duration
 	running bool
 	style   string
+	renderer *renderer.Renderer
+	theme    *theme.Theme
 }
 
 var spinnerStyles = map[string][]string{
@@ -119,9 +146,36 @@ func (s *Spinner) WithLabel(label string) *Spinner {
 	return s
 }
 
+// WithRenderer draws the spinner with r instead of a renderer on the standard streams
+func (s *Spinner) WithRenderer(r *renderer.Renderer) *Spinner {
+	s.renderer = r
+	return s
+}
+
+// WithTheme draws the spinner with t instead of theme.Current()
+func (s *Spinner) WithTheme(t *theme.Theme) *Spinner {
+	s.theme = t
+	return s
+}
+
+// currentTheme returns the theme the spinner is drawn with
+func (s *Spinner) currentTheme() *theme.Theme {
+	if s.theme != nil {
+		return s.theme
+	}
+	return theme.Current()
+}
+
+func (s *Spinner) newRenderer() *renderer.Renderer {
+	if s.renderer != nil {
+		return s.renderer
+	}
+	return renderer.New()
+}
+
 func (s *Spinner) Start() {
 	s.running = true
-	r := renderer.New()
+	r := s.newRenderer()
 	
 	go func() {
 		i := 0
@@ -129,7 +183,7 @@ func (s *Spinner) Start() {
 			r.MoveCursorUp(1)
 			r.ClearLine()
 			
-			r.WriteStyled(s.frames[i], theme.Current().Primary.Foreground)
+			r.WriteStyled(s.frames[i], s.currentTheme().Primary.Sequence())
 			if s.label != "" {
 				r.Write(" " + s.label)
 			}
@@ -146,7 +200,7 @@ func (s *Spinner) Stop() {
 	s.running = false
 	time.Sleep(s.delay)
 	
-	r := renderer.New()
+	r := s.newRenderer()
 	defer r.Close()
 	r.MoveCursorUp(1)
 	r.ClearLine()
@@ -0,0 +1,32 @@
+package renderer
+
+import "io"
+
+// WithAltScreen makes Init switch to the alternate screen buffer and
+// Restore switch back, leaving the primary screen and its scrollback as
+// they were.
+func (r *Renderer) WithAltScreen() *Renderer {
+	r.altScreen = true
+	return r
+}
+
+// enterAltScreen switches to the alternate screen unless the renderer r is
+// nested in already did
+func (r *Renderer) enterAltScreen() {
+	r.inAltScreen = true
+	if r.outer == nil || !r.outer.inAltScreen {
+		io.WriteString(r.out, "\033[?1049h")
+	}
+}
+
+// leaveAltScreen switches back to the primary screen unless outer, the
+// renderer r was nested in, still uses the alternate one
+func (r *Renderer) leaveAltScreen(outer *Renderer) {
+	if !r.inAltScreen {
+		return
+	}
+	r.inAltScreen = false
+	if outer == nil || !outer.inAltScreen {
+		io.WriteString(r.out, "\033[?1049l")
+	}
+}
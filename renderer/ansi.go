@@ -0,0 +1,284 @@
+package renderer
+
+import (
+	"strings"
+
+	"github.com/vynazevedo/termx/textwidth"
+)
+
+// TokenKind identifies what a Token of a styled string holds
+type TokenKind int
+
+const (
+	// TokenText is printable text, including control characters such as \n
+	TokenText TokenKind = iota
+	// TokenSGR is a Select Graphic Rendition sequence (colors and attributes)
+	TokenSGR
+	// TokenCSI is any other Control Sequence Introducer sequence, such as a cursor move
+	TokenCSI
+	// TokenOSC is an Operating System Command, such as a hyperlink or window title
+	TokenOSC
+	// TokenDCS is a Device Control String or one of the other string
+	// sequences (SOS, PM, APC) terminated by ST
+	TokenDCS
+	// TokenEscape is a short escape sequence that is not one of the above
+	TokenEscape
+)
+
+// Token is a run of text or a single complete escape sequence
+type Token struct {
+	Kind  TokenKind
+	Value string
+}
+
+// Tokenize splits s into text runs and escape sequences. An escape sequence
+// that is cut off at the end of s is returned whole as its own token.
+func Tokenize(s string) []Token {
+	var tokens []Token
+	start := 0
+	for i := 0; i < len(s); {
+		if s[i] != '\033' {
+			i++
+			continue
+		}
+		if i > start {
+			tokens = append(tokens, Token{Kind: TokenText, Value: s[start:i]})
+		}
+		kind, n := scanEscape(s[i:])
+		tokens = append(tokens, Token{Kind: kind, Value: s[i : i+n]})
+		i += n
+		start = i
+	}
+	if start < len(s) {
+		tokens = append(tokens, Token{Kind: TokenText, Value: s[start:]})
+	}
+	return tokens
+}
+
+// scanEscape returns the kind and length of the escape sequence at the start of s
+func scanEscape(s string) (TokenKind, int) {
+	if len(s) < 2 {
+		return TokenEscape, len(s)
+	}
+
+	switch s[1] {
+	case '[':
+		i := 2
+		for i < len(s) && s[i] >= 0x20 && s[i] <= 0x3f {
+			i++
+		}
+		if i == len(s) {
+			return TokenCSI, i
+		}
+		if s[i] == 'm' {
+			return TokenSGR, i + 1
+		}
+		if s[i] >= 0x40 && s[i] <= 0x7e {
+			return TokenCSI, i + 1
+		}
+		// Malformed: stop before the offending byte
+		return TokenCSI, i
+	case ']':
+		return TokenOSC, scanString(s, true)
+	case 'P', 'X', '^', '_':
+		return TokenDCS, scanString(s, false)
+	}
+
+	i := 1
+	for i < len(s) && s[i] >= 0x20 && s[i] <= 0x2f {
+		i++
+	}
+	if i < len(s) {
+		i++
+	}
+	return TokenEscape, i
+}
+
+// scanString returns the length of a string sequence ending in ST (ESC \).
+// OSC sequences may also be terminated by BEL.
+func scanString(s string, bel bool) int {
+	for i := 2; i < len(s); i++ {
+		switch {
+		case bel && s[i] == '\a':
+			return i + 1
+		case s[i] == '\033' && i+1 < len(s) && s[i+1] == '\\':
+			return i + 2
+		}
+	}
+	return len(s)
+}
+
+// StripANSI removes every escape sequence from s
+func StripANSI(s string) string {
+	if !strings.Contains(s, "\033") {
+		return s
+	}
+	var out strings.Builder
+	for _, tok := range Tokenize(s) {
+		if tok.Kind == TokenText {
+			out.WriteString(tok.Value)
+		}
+	}
+	return out.String()
+}
+
+// StringWidth returns the number of cells s occupies, ignoring escape sequences
+func StringWidth(s string) int {
+	return textwidth.String(StripANSI(s))
+}
+
+// Truncate shortens a styled string to at most width cells, appending tail
+// when something was cut. Escape sequences after the cut point are kept so
+// that resets and closing hyperlinks still take effect.
+func Truncate(s string, width int, tail string) string {
+	if StringWidth(s) <= width {
+		return s
+	}
+
+	if width <= 0 {
+		return ""
+	}
+
+	limit := width - StringWidth(tail)
+	if limit < 0 {
+		return Truncate(tail, width, "")
+	}
+
+	var out strings.Builder
+	used := 0
+	cut := false
+	for _, tok := range Tokenize(s) {
+		if tok.Kind != TokenText {
+			out.WriteString(tok.Value)
+			continue
+		}
+		text := tok.Value
+		for !cut && text != "" {
+			cluster, w := textwidth.FirstCluster(text)
+			if used+w > limit {
+				out.WriteString(tail)
+				cut = true
+				break
+			}
+			out.WriteString(cluster)
+			used += w
+			text = text[len(cluster):]
+		}
+	}
+	return out.String()
+}
+
+// PadRight appends spaces to a styled string until it is width cells wide
+func PadRight(s string, width int) string {
+	if n := width - StringWidth(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
+
+// wrapItem is a grapheme cluster or an escape sequence of a string being wrapped
+type wrapItem struct {
+	kind  TokenKind
+	value string
+	width int
+}
+
+// Wrap breaks a styled string into lines of at most width cells, breaking
+// at spaces where possible and inside words that do not fit on a line of
+// their own. Each line is self-contained: the style active at its start is
+// repeated and an active style is reset at its end.
+func Wrap(s string, width int) []string {
+	if width <= 0 {
+		return []string{s}
+	}
+
+	var lines [][]wrapItem
+	var line, spaces, word []wrapItem
+	lineWidth, spaceWidth, wordWidth := 0, 0, 0
+
+	endLine := func() {
+		lines = append(lines, line)
+		line, spaces = nil, nil
+		lineWidth, spaceWidth = 0, 0
+	}
+	flushWord := func() {
+		if lineWidth > 0 && lineWidth+spaceWidth+wordWidth > width {
+			endLine()
+		}
+		line = append(line, spaces...)
+		line = append(line, word...)
+		lineWidth += spaceWidth + wordWidth
+		spaces, word = nil, nil
+		spaceWidth, wordWidth = 0, 0
+	}
+
+	for _, tok := range Tokenize(s) {
+		if tok.Kind != TokenText {
+			word = append(word, wrapItem{kind: tok.Kind, value: tok.Value})
+			continue
+		}
+		for text := tok.Value; text != ""; {
+			cluster, w := textwidth.FirstCluster(text)
+			text = text[len(cluster):]
+
+			switch cluster {
+			case "\n":
+				flushWord()
+				endLine()
+				continue
+			case " ":
+				flushWord()
+				spaces = append(spaces, wrapItem{value: cluster, width: w})
+				spaceWidth += w
+				continue
+			}
+
+			word = append(word, wrapItem{value: cluster, width: w})
+			wordWidth += w
+			if lineWidth+spaceWidth+wordWidth <= width {
+				continue
+			}
+			if lineWidth > 0 {
+				endLine()
+			}
+			spaces, spaceWidth = nil, 0
+			for wordWidth > width {
+				// The word alone is too long: fill the line with its head
+				used, n := 0, 0
+				for n < len(word) && used+word[n].width <= width {
+					used += word[n].width
+					n++
+				}
+				if n == 0 {
+					// A single cluster wider than the line
+					n, used = 1, word[0].width
+				}
+				line = append(line, word[:n]...)
+				lineWidth = used
+				word = append([]wrapItem(nil), word[n:]...)
+				wordWidth -= used
+				endLine()
+			}
+		}
+	}
+	flushWord()
+	endLine()
+
+	out := make([]string, 0, len(lines))
+	style := ""
+	for _, items := range lines {
+		var b strings.Builder
+		b.WriteString(style)
+		for _, item := range items {
+			b.WriteString(item.value)
+			if item.kind == TokenSGR {
+				style = applySGR(style, item.value)
+			}
+		}
+		if style != "" {
+			b.WriteString("\033[0m")
+		}
+		out = append(out, b.String())
+	}
+	return out
+}
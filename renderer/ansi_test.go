@@ -0,0 +1,110 @@
+package renderer_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vynazevedo/termx/renderer"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []renderer.Token
+	}{
+		{"plain", []renderer.Token{{renderer.TokenText, "plain"}}},
+		{"\033[1;31mred\033[0m", []renderer.Token{
+			{renderer.TokenSGR, "\033[1;31m"},
+			{renderer.TokenText, "red"},
+			{renderer.TokenSGR, "\033[0m"},
+		}},
+		{"a\033[2Jb", []renderer.Token{
+			{renderer.TokenText, "a"},
+			{renderer.TokenCSI, "\033[2J"},
+			{renderer.TokenText, "b"},
+		}},
+		{"\033]8;;http://x\033\\link\033]8;;\a", []renderer.Token{
+			{renderer.TokenOSC, "\033]8;;http://x\033\\"},
+			{renderer.TokenText, "link"},
+			{renderer.TokenOSC, "\033]8;;\a"},
+		}},
+		{"cut\033[3", []renderer.Token{
+			{renderer.TokenText, "cut"},
+			{renderer.TokenCSI, "\033[3"},
+		}},
+	}
+	for _, tt := range tests {
+		if got := renderer.Tokenize(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestStringWidth(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"\033[31mred\033[0m", 3},
+		{"\033]8;;http://x\033\\日本\033]8;;\033\\", 4},
+	}
+	for _, tt := range tests {
+		if got := renderer.StringWidth(tt.in); got != tt.want {
+			t.Errorf("StringWidth(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		tail  string
+		want  string
+	}{
+		{"short", 10, "…", "short"},
+		{"\033[31mhello world\033[0m", 6, "…", "\033[31mhello…\033[0m"},
+		{"日本語", 3, "", "日"},
+		{"hello", 2, "...", ".."},
+		{"hello", 0, "...", ""},
+		// Negative widths used to recurse until the stack overflowed
+		{"", -4, "", ""},
+		{"hello", -1, "...", ""},
+	}
+	for _, tt := range tests {
+		if got := renderer.Truncate(tt.in, tt.width, tt.tail); got != tt.want {
+			t.Errorf("Truncate(%q, %d, %q) = %q, want %q", tt.in, tt.width, tt.tail, got, tt.want)
+		}
+	}
+}
+
+func FuzzTruncate(f *testing.F) {
+	f.Add("\033[1mbold\033[0m text", 5, "…")
+	f.Add("", -4, "")
+	f.Fuzz(func(t *testing.T, s string, width int, tail string) {
+		got := renderer.Truncate(s, width, tail)
+		if w := renderer.StringWidth(got); w > max(width, 0) && got != s {
+			t.Errorf("Truncate(%q, %d, %q) = %q is %d cells wide", s, width, tail, got, w)
+		}
+	})
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		want  []string
+	}{
+		{"the quick brown fox", 10, []string{"the quick", "brown fox"}},
+		{"abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{"one\ntwo", 10, []string{"one", "two"}},
+		{"\033[31mred words\033[0m", 5, []string{"\033[31mred\033[0m", "\033[31mwords\033[0m"}},
+		{"anything", 0, []string{"anything"}},
+	}
+	for _, tt := range tests {
+		if got := renderer.Wrap(tt.in, tt.width); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Wrap(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+		}
+	}
+}
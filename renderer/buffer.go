@@ -0,0 +1,176 @@
+package renderer
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/vynazevedo/termx/textwidth"
+)
+
+// Cell is a single character position of the frame buffer. A wide character
+// occupies two cells: the first holds the character with Width 2 and the
+// second is a continuation cell with Width 0.
+type Cell struct {
+	Rune  rune
+	Comb  string // combining marks and joined runes of the same grapheme
+	Width int
+	Style string
+}
+
+var blankCell = Cell{Rune: ' ', Width: 1}
+
+// buffer is a fixed-size grid of cells addressed by column and row
+type buffer struct {
+	width  int
+	height int
+	cells  []Cell
+}
+
+func newBuffer(width, height int) *buffer {
+	if width < 0 {
+		width = 0
+	}
+	if height < 0 {
+		height = 0
+	}
+	b := &buffer{
+		width:  width,
+		height: height,
+		cells:  make([]Cell, width*height),
+	}
+	b.clear()
+	return b
+}
+
+func (b *buffer) clear() {
+	for i := range b.cells {
+		b.cells[i] = blankCell
+	}
+}
+
+func (b *buffer) inside(x, y int) bool {
+	return x >= 0 && y >= 0 && x < b.width && y < b.height
+}
+
+func (b *buffer) cell(x, y int) Cell {
+	if !b.inside(x, y) {
+		return blankCell
+	}
+	return b.cells[y*b.width+x]
+}
+
+func (b *buffer) set(x, y int, c Cell) {
+	if !b.inside(x, y) {
+		return
+	}
+	i := y*b.width + x
+	b.detach(x, y)
+	if c.Width == 2 {
+		if x+1 >= b.width {
+			// Half a wide character cannot be shown at the right edge
+			c = Cell{Rune: ' ', Width: 1, Style: c.Style}
+		} else {
+			b.detach(x+1, y)
+			b.cells[i+1] = Cell{Width: 0, Style: c.Style}
+		}
+	}
+	b.cells[i] = c
+}
+
+// detach blanks the other half of a wide character covering x, y, so that
+// overwriting one half never leaves a broken glyph behind.
+func (b *buffer) detach(x, y int) {
+	i := y*b.width + x
+	switch b.cells[i].Width {
+	case 0:
+		if x > 0 {
+			b.cells[i-1] = blankCell
+		}
+	case 2:
+		if x+1 < b.width {
+			b.cells[i+1] = blankCell
+		}
+	}
+	b.cells[i] = blankCell
+}
+
+func (b *buffer) copyFrom(src *buffer) {
+	copy(b.cells, src.cells)
+}
+
+// bounds returns the area covered by the buffer
+func (b *buffer) bounds() Rect {
+	return Rect{Width: b.width, Height: b.height}
+}
+
+// drawText writes text starting at x, y. SGR sequences embedded in text
+// change the style of the following cells; other escape sequences are dropped.
+// Each grapheme cluster takes as many cells as its display width. Cells
+// outside clip are left untouched.
+func (b *buffer) drawText(x, y int, text string, clip Rect) {
+	style := ""
+	startX := x
+
+	for _, tok := range Tokenize(text) {
+		switch tok.Kind {
+		case TokenSGR:
+			style = applySGR(style, tok.Value)
+			continue
+		case TokenText:
+		default:
+			continue
+		}
+
+		for s := tok.Value; s != ""; {
+			switch ch := s[0]; {
+			case ch == '\n':
+				y++
+				x = startX
+				s = s[1:]
+			case ch == '\r':
+				x = startX
+				s = s[1:]
+			case ch < 32:
+				// Control characters have no cell representation
+				s = s[1:]
+			default:
+				cluster, width := textwidth.FirstCluster(s)
+				s = s[len(cluster):]
+				base, size := utf8.DecodeRuneInString(cluster)
+				if width == 0 {
+					// A lone combining mark joins the character before it
+					if b.inside(x-1, y) && clip.Contains(x-1, y) && b.cells[y*b.width+x-1].Width > 0 {
+						b.cells[y*b.width+x-1].Comb += cluster
+					}
+					continue
+				}
+				switch {
+				case !clip.Contains(x, y):
+				case width == 2 && !clip.Contains(x+1, y):
+					// Half a wide character cannot be shown at the clip edge
+					b.set(x, y, Cell{Rune: ' ', Width: 1, Style: style})
+				default:
+					b.set(x, y, Cell{Rune: base, Comb: cluster[size:], Width: width, Style: style})
+				}
+				x += width
+			}
+		}
+	}
+}
+
+// applySGR folds an SGR sequence into the current style. A reset clears
+// everything accumulated so far.
+func applySGR(style, seq string) string {
+	params := strings.TrimSuffix(strings.TrimPrefix(seq, "\033["), "m")
+	if params == "" {
+		return ""
+	}
+	if n, err := strconv.Atoi(params); err == nil && n == 0 {
+		return ""
+	}
+	if strings.HasPrefix(params, "0;") {
+		return "\033[" + strings.TrimPrefix(params, "0;") + "m"
+	}
+	return style + seq
+}
@@ -0,0 +1,68 @@
+package renderer
+
+import "testing"
+
+// row returns the runes of row y of b as a string
+func row(b *buffer, y int) string {
+	var s []rune
+	for x := 0; x < b.width; x++ {
+		s = append(s, b.cell(x, y).Rune)
+	}
+	return string(s)
+}
+
+func TestDrawText(t *testing.T) {
+	all := Rect{Width: 10, Height: 2}
+	tests := []struct {
+		name string
+		x, y int
+		text string
+		clip Rect
+		rows []string
+	}{
+		{"plain", 0, 0, "hello", all, []string{"hello     ", "          "}},
+		{"offset", 3, 1, "abc", all, []string{"          ", "   abc    "}},
+		{"newline keeps the column", 2, 0, "ab\ncd", all, []string{"  ab      ", "  cd      "}},
+		{"clipped", 7, 0, "abcdef", all, []string{"       abc", "          "}},
+		{"sgr takes no cells", 0, 0, "\033[1mbold\033[0m!", all, []string{"bold!     ", "          "}},
+		{"other escapes are dropped", 0, 0, "a\033[2Kb\033[?25lc", all, []string{"abc       ", "          "}},
+		{"control characters", 0, 0, "a\tb\ac", all, []string{"abc       ", "          "}},
+		{"wide characters", 0, 0, "日本x", all, []string{"日\x00本\x00x     ", "          "}},
+		{"clipped to an area", 0, 0, "abcdef\nghijkl", Rect{X: 2, Y: 1, Width: 3, Height: 1}, []string{"          ", "  ijk     "}},
+	}
+	for _, tt := range tests {
+		b := newBuffer(10, 2)
+		b.drawText(tt.x, tt.y, tt.text, tt.clip)
+		for y, want := range tt.rows {
+			if got := row(b, y); got != want {
+				t.Errorf("%s: row %d = %q, want %q", tt.name, y, got, want)
+			}
+		}
+	}
+}
+
+func TestDrawTextStyles(t *testing.T) {
+	b := newBuffer(10, 1)
+	b.drawText(0, 0, "a\033[1mb\033[31mc\033[0md\033[0;32me", Rect{Width: 10, Height: 1})
+
+	want := []string{"", "\033[1m", "\033[1m\033[31m", "", "\033[32m"}
+	for x, style := range want {
+		if got := b.cell(x, 0).Style; got != style {
+			t.Errorf("cell %d style = %q, want %q", x, got, style)
+		}
+	}
+}
+
+func TestBufferOutside(t *testing.T) {
+	b := newBuffer(2, 2)
+	b.set(-1, 0, Cell{Rune: 'x', Width: 1})
+	b.set(2, 1, Cell{Rune: 'x', Width: 1})
+	if got := b.cell(5, 5); got != blankCell {
+		t.Errorf("cell(5, 5) = %+v, want a blank cell", got)
+	}
+	for y := 0; y < 2; y++ {
+		if got := row(b, y); got != "  " {
+			t.Errorf("row %d = %q, want it blank", y, got)
+		}
+	}
+}
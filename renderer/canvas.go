@@ -0,0 +1,133 @@
+package renderer
+
+import "strings"
+
+// Rect is a rectangular area of the screen, in cells
+type Rect struct {
+	X, Y          int
+	Width, Height int
+}
+
+// Empty reports whether the area has no cells
+func (a Rect) Empty() bool {
+	return a.Width <= 0 || a.Height <= 0
+}
+
+// Contains reports whether the cell at x, y is inside the area
+func (a Rect) Contains(x, y int) bool {
+	return x >= a.X && y >= a.Y && x < a.X+a.Width && y < a.Y+a.Height
+}
+
+// Intersect returns the area covered by both a and b
+func (a Rect) Intersect(b Rect) Rect {
+	x0, y0 := max(a.X, b.X), max(a.Y, b.Y)
+	x1, y1 := min(a.X+a.Width, b.X+b.Width), min(a.Y+a.Height, b.Y+b.Height)
+	if x1 <= x0 || y1 <= y0 {
+		return Rect{X: x0, Y: y0}
+	}
+	return Rect{X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0}
+}
+
+// Inset returns the area shrunk by n cells on every side
+func (a Rect) Inset(n int) Rect {
+	a.X += n
+	a.Y += n
+	a.Width = max(a.Width-2*n, 0)
+	a.Height = max(a.Height-2*n, 0)
+	return a
+}
+
+// Center returns an area of width by height centered in a, shrunk to fit
+func (a Rect) Center(width, height int) Rect {
+	width, height = min(width, a.Width), min(height, a.Height)
+	return Rect{
+		X:      a.X + (a.Width-width)/2,
+		Y:      a.Y + (a.Height-height)/2,
+		Width:  width,
+		Height: height,
+	}
+}
+
+// Canvas draws into the frame of a renderer, clipped to an area. It uses
+// screen coordinates, the same as mouse events, so a widget can map clicks
+// with the positions it drew at.
+type Canvas struct {
+	r        *Renderer
+	clip     Rect
+	noCursor bool
+}
+
+// Canvas returns a canvas covering the whole frame. What is drawn on it
+// reaches the terminal with the next Flush.
+func (r *Renderer) Canvas() *Canvas {
+	return &Canvas{r: r, clip: r.back.bounds()}
+}
+
+// Bounds returns the area the canvas draws in
+func (c *Canvas) Bounds() Rect {
+	return c.clip
+}
+
+// Clip returns a canvas that only draws in the part of area inside c
+func (c *Canvas) Clip(area Rect) *Canvas {
+	return &Canvas{r: c.r, clip: c.clip.Intersect(area), noCursor: c.noCursor}
+}
+
+// WithoutCursor returns a canvas on which SetCursor does nothing, for
+// drawing widgets that do not have the focus
+func (c *Canvas) WithoutCursor() *Canvas {
+	return &Canvas{r: c.r, clip: c.clip, noCursor: true}
+}
+
+// Print draws text at x, y like Renderer.Print, dropping what falls outside
+// the canvas
+func (c *Canvas) Print(x, y int, text string) {
+	c.r.back.drawText(x, y, text, c.clip)
+}
+
+// PrintCentered draws text on row y, centered in the canvas
+func (c *Canvas) PrintCentered(y int, text string) {
+	x := c.clip.X + (c.clip.Width-StringWidth(text))/2
+	c.Print(max(x, c.clip.X), y, text)
+}
+
+// Fill paints area with blanks in the given SGR style
+func (c *Canvas) Fill(area Rect, style string) {
+	area = area.Intersect(c.clip)
+	line := style + strings.Repeat(" ", area.Width)
+	for y := area.Y; y < area.Y+area.Height; y++ {
+		c.Print(area.X, y, line)
+	}
+}
+
+// Box draws a single-line border around area, with title centered on top
+func (c *Canvas) Box(area Rect, title string) {
+	c.StyledBox(area, title, "")
+}
+
+// StyledBox is like Box but draws the border in the given SGR style. The
+// title keeps its own styling.
+func (c *Canvas) StyledBox(area Rect, title, style string) {
+	if area.Width < 2 || area.Height < 2 {
+		return
+	}
+	inner := strings.Repeat("─", area.Width-2)
+	c.Print(area.X, area.Y, style+"┌"+inner+"┐")
+	if title != "" {
+		titleX := area.X + (area.Width-StringWidth(title)-2)/2
+		c.Print(titleX, area.Y, style+"┤\033[0m"+title+style+"├")
+	}
+	for y := area.Y + 1; y < area.Y+area.Height-1; y++ {
+		c.Print(area.X, y, style+"│")
+		c.Print(area.X+area.Width-1, y, style+"│")
+	}
+	c.Print(area.X, area.Y+area.Height-1, style+"└"+inner+"┘")
+}
+
+// SetCursor places the visible cursor at x, y if it is inside the canvas,
+// unless the canvas was made with WithoutCursor
+func (c *Canvas) SetCursor(x, y int) {
+	if !c.noCursor && c.clip.Contains(x, y) {
+		c.r.SetCursor(x, y)
+	}
+}
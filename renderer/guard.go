@@ -0,0 +1,237 @@
+package renderer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// ErrTerminated is returned by the reads of a renderer that held the
+// terminal when the process received a termination signal
+var ErrTerminated = errors.New("terminated by a signal")
+
+// guard keeps track of the renderers that currently hold the terminal, so
+// that it can be handed back to the shell when the process is killed or
+// stopped by a signal, or a panic unwinds past the code that would normally
+// restore it.
+var guard struct {
+	sync.Mutex
+	active map[*Renderer]struct{}
+	stop   chan struct{}
+
+	// Channels given to Notify and the signals they take; nil means all
+	handlers map[chan<- os.Signal][]os.Signal
+}
+
+// register adds r to the active renderers and starts watching for
+// termination and suspend signals when it is the first one
+func register(r *Renderer) {
+	guard.Lock()
+	defer guard.Unlock()
+
+	if guard.active == nil {
+		guard.active = make(map[*Renderer]struct{})
+	}
+	guard.active[r] = struct{}{}
+	if len(guard.active) > 1 {
+		return
+	}
+
+	signals := make(chan os.Signal, 1)
+	suspend := make(chan os.Signal, 1)
+	stop := make(chan struct{})
+	guard.stop = stop
+	signal.Notify(signals, terminationSignals...)
+	if len(suspendSignals) > 0 {
+		// Notify without signals would relay all of them
+		signal.Notify(suspend, suspendSignals...)
+	}
+
+	go func() {
+		defer signal.Stop(signals)
+		defer signal.Stop(suspend)
+		for {
+			select {
+			case sig := <-signals:
+				if terminate(sig) {
+					// The program got the signal too and shuts down on
+					// its own; the loop ends once stop is closed
+					continue
+				}
+				signal.Stop(signals)
+				raise(sig)
+				return
+			case <-suspend:
+				Suspend()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// unregister removes r from the active renderers and stops watching for
+// signals when none is left
+func unregister(r *Renderer) {
+	guard.Lock()
+	defer guard.Unlock()
+
+	if _, ok := guard.active[r]; !ok {
+		return
+	}
+	delete(guard.active, r)
+	if len(guard.active) == 0 {
+		close(guard.stop)
+		guard.stop = nil
+	}
+}
+
+// terminate restores every terminal held by a renderer after sig arrived
+// and unregisters the renderers, whose reads return ErrTerminated from then
+// on. It reports whether the program handles sig through Notify.
+func terminate(sig os.Signal) bool {
+	guard.Lock()
+	defer guard.Unlock()
+
+	for r := range guard.active {
+		r.reset()
+		r.terminate()
+		delete(guard.active, r)
+	}
+	if guard.stop != nil {
+		close(guard.stop)
+		guard.stop = nil
+	}
+
+	for _, sigs := range guard.handlers {
+		if len(sigs) == 0 {
+			return true
+		}
+		for _, s := range sigs {
+			if s == sig {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Notify relays sig to c like signal.Notify, and tells the renderers that
+// the program handles sig itself. A termination signal that arrives while a
+// renderer holds the terminal then only restores the terminal and ends the
+// renderer's reads with ErrTerminated; without a handler registered here it
+// is raised again with its default action, which ends the process.
+func Notify(c chan<- os.Signal, sig ...os.Signal) {
+	guard.Lock()
+	if guard.handlers == nil {
+		guard.handlers = make(map[chan<- os.Signal][]os.Signal)
+	}
+	sigs, ok := guard.handlers[c]
+	if len(sig) == 0 || (ok && sigs == nil) {
+		guard.handlers[c] = nil
+	} else {
+		guard.handlers[c] = append(sigs, sig...)
+	}
+	guard.Unlock()
+
+	signal.Notify(c, sig...)
+}
+
+// StopNotify stops relaying signals to c, undoing Notify
+func StopNotify(c chan<- os.Signal) {
+	signal.Stop(c)
+
+	guard.Lock()
+	delete(guard.handlers, c)
+	guard.Unlock()
+}
+
+// NotifyContext is signal.NotifyContext for signals registered through
+// Notify: the returned context is done when one of them arrives, when stop
+// is called or when parent is done.
+func NotifyContext(parent context.Context, sig ...os.Signal) (ctx context.Context, stop context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	c := make(chan os.Signal, 1)
+	Notify(c, sig...)
+	go func() {
+		select {
+		case <-c:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		cancel()
+		StopNotify(c)
+	}
+}
+
+// RestoreAll puts every terminal held by a renderer back in the state it
+// was in before Init: cooked mode, visible cursor and the primary screen.
+// It does not wait for the renderers' own users and is meant for the
+// moment the process is about to die; Restore must still be used for
+// normal cleanup.
+func RestoreAll() {
+	guard.Lock()
+	defer guard.Unlock()
+
+	for r := range guard.active {
+		r.reset()
+	}
+}
+
+// RestoreOnPanic restores every terminal held by a renderer when the
+// calling goroutine is panicking, then resumes panicking. Components defer
+// it in their event loops; programs can defer it in goroutines of their own
+// that run while a prompt is on screen.
+func RestoreOnPanic() {
+	if v := recover(); v != nil {
+		RestoreAll()
+		panic(v)
+	}
+}
+
+// setupSequence enables the terminal modes the renderer uses
+func (r *Renderer) setupSequence() string {
+	seq := "\033[?2004h"
+	if r.mouse {
+		seq += "\033[?1000h\033[?1002h\033[?1006h"
+	}
+	return seq + "\033[?25l"
+}
+
+// resetSequence undoes the terminal modes enabled by Init
+func (r *Renderer) resetSequence() string {
+	seq := "\033[0m\033[?2004l"
+	if r.mouse {
+		seq += "\033[?1006l\033[?1002l\033[?1000l"
+	}
+	return seq + "\033[?25h"
+}
+
+// terminate makes the reads of r return ErrTerminated. Like reset it is
+// safe while another goroutine is still using r.
+func (r *Renderer) terminate() {
+	r.terminated.Store(true)
+	select {
+	case r.killed <- struct{}{}:
+	default:
+	}
+}
+
+// reset hands the terminal back without touching the renderer state, so
+// that it is safe while another goroutine is still using r
+func (r *Renderer) reset() {
+	io.WriteString(r.out, r.resetSequence())
+	if r.inAltScreen {
+		io.WriteString(r.out, "\033[?1049l")
+	}
+	if r.oldState != nil {
+		term.Restore(int(r.in.(fileDescriptor).Fd()), r.oldState)
+	}
+}
@@ -0,0 +1,22 @@
+//go:build !unix
+
+package renderer
+
+import (
+	"os"
+	"syscall"
+)
+
+var terminationSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// raise ends the process the way an unhandled interrupt would
+func raise(sig os.Signal) {
+	os.Exit(1)
+}
+
+// canSuspend reports whether the platform has job control
+const canSuspend = false
+
+var suspendSignals []os.Signal
+
+func stopProcess() {}
@@ -0,0 +1,36 @@
+//go:build unix
+
+package renderer
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+var terminationSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP}
+
+// raise delivers sig to the process again. With no other handler left it
+// takes its default action, so that the exit status tells the parent which
+// signal ended the process; a program that handles sig itself receives it
+// and can shut down on its own.
+func raise(sig os.Signal) {
+	syscall.Kill(os.Getpid(), sig.(syscall.Signal))
+}
+
+// canSuspend reports whether the platform has job control
+const canSuspend = true
+
+var suspendSignals = []os.Signal{syscall.SIGTSTP}
+
+// stopProcess stops the process group the way the terminal does for
+// Ctrl+Z and returns once SIGCONT arrives. SIGSTOP is used because the
+// runtime keeps catching SIGTSTP once it has been passed to signal.Notify.
+func stopProcess() {
+	continued := make(chan os.Signal, 1)
+	signal.Notify(continued, syscall.SIGCONT)
+	defer signal.Stop(continued)
+
+	syscall.Kill(0, syscall.SIGSTOP)
+	<-continued
+}
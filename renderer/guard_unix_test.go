@@ -0,0 +1,89 @@
+//go:build unix
+
+package renderer_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/vynazevedo/termx/renderer"
+)
+
+// A program that handles SIGTERM itself must get the signal exactly once
+// while a renderer holds the terminal, and the renderer must stop reading
+func TestTerminationSignalReachesProgramHandler(t *testing.T) {
+	received := make(chan os.Signal, 2)
+	renderer.Notify(received, syscall.SIGTERM)
+	defer renderer.StopNotify(received)
+
+	in, w := io.Pipe()
+	defer w.Close()
+	r := renderer.NewWithIO(in, io.Discard, nil)
+	if err := r.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Restore()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := r.ReadInput()
+		done <- err
+	}()
+	syscall.Kill(os.Getpid(), syscall.SIGTERM)
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, renderer.ErrTerminated) {
+			t.Fatalf("ReadInput() error = %v, want %v", err, renderer.ErrTerminated)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("SIGTERM did not end the renderer's read")
+	}
+	if _, err := r.ReadInput(); !errors.Is(err, renderer.ErrTerminated) {
+		t.Errorf("ReadInput() after the signal error = %v, want %v", err, renderer.ErrTerminated)
+	}
+
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("SIGTERM did not reach the program's handler")
+	}
+	select {
+	case <-received:
+		t.Error("SIGTERM reached the program's handler twice")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+// Without a handler of its own the process must end by the signal, so that
+// its parent sees why it stopped
+func TestTerminationSignalEndsProgramWithoutHandler(t *testing.T) {
+	if os.Getenv("TERMX_GUARD_CHILD") == "1" {
+		in, w := io.Pipe()
+		defer w.Close()
+		r := renderer.NewWithIO(in, io.Discard, nil)
+		if err := r.Init(); err != nil {
+			t.Fatal(err)
+		}
+		syscall.Kill(os.Getpid(), syscall.SIGTERM)
+		r.ReadInput()
+		time.Sleep(5 * time.Second)
+		os.Exit(0)
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestTerminationSignalEndsProgramWithoutHandler$")
+	cmd.Env = append(os.Environ(), "TERMX_GUARD_CHILD=1")
+	err := cmd.Run()
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) {
+		t.Fatalf("child error = %v, want it killed by SIGTERM", err)
+	}
+	if status, ok := exit.Sys().(syscall.WaitStatus); !ok || !status.Signaled() || status.Signal() != syscall.SIGTERM {
+		t.Errorf("child ended with %v, want it killed by SIGTERM", exit)
+	}
+}
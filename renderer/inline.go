@@ -0,0 +1,77 @@
+package renderer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DrawInline draws lines at the cursor position instead of on the frame
+// buffer, leaving the output above untouched. Each call replaces the lines
+// drawn by the previous one, rewriting only those that changed, so a prompt
+// can redraw itself in place below the rest of the program's output. Lines
+// are cut to the terminal width. A cursor placed with SetCursor is shown at
+// the given column and row, counted from the first line of the region.
+func (r *Renderer) DrawInline(lines []string) {
+	var out strings.Builder
+	out.WriteString("\033[?25l")
+	r.moveToInlineTop(&out)
+
+	for i, line := range lines {
+		if i > 0 {
+			out.WriteString("\r\n")
+		}
+		if r.inlineLines != nil && i < len(r.inlineLines) && r.inlineLines[i] == line {
+			continue
+		}
+		out.WriteString("\033[2K")
+		out.WriteString(Truncate(line, r.width, ""))
+	}
+	if len(lines) < len(r.inlineLines) {
+		// Erase the lines left over from a taller previous frame
+		out.WriteString("\033[J")
+	}
+
+	r.inlineRow = max(len(lines)-1, 0)
+	if r.cursorSet {
+		if up := r.inlineRow - r.cursorY; up > 0 {
+			fmt.Fprintf(&out, "\033[%dA", up)
+		}
+		fmt.Fprintf(&out, "\r\033[%dG\033[?25h", r.cursorX+1)
+		r.inlineRow = r.cursorY
+		r.cursorSet = false
+	}
+
+	r.inlineLines = append([]string{}, lines...)
+	io.WriteString(r.out, r.filter(out.String()))
+}
+
+// ClearInline erases the region drawn by DrawInline and leaves the cursor
+// where it started, so that a summary can be written in its place
+func (r *Renderer) ClearInline() {
+	if r.inlineLines == nil {
+		return
+	}
+	var out strings.Builder
+	r.moveToInlineTop(&out)
+	out.WriteString("\033[J")
+	io.WriteString(r.out, out.String())
+	r.inlineLines = nil
+	r.inlineRow = 0
+}
+
+// moveToInlineTop moves the cursor to the first column of the region
+func (r *Renderer) moveToInlineTop(out *strings.Builder) {
+	out.WriteString("\r")
+	if r.inlineRow > 0 {
+		fmt.Fprintf(out, "\033[%dA", r.inlineRow)
+	}
+}
+
+// invalidateInline makes the next DrawInline rewrite every line, for when
+// the terminal may have reflowed them
+func (r *Renderer) invalidateInline() {
+	if r.inlineLines != nil {
+		r.inlineLines = make([]string, 0, len(r.inlineLines))
+	}
+}
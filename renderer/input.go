@@ -1,8 +1,15 @@
 package renderer
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 type Key int
@@ -25,76 +32,539 @@ const (
 	KeyPageUp
 	KeyPageDown
 	KeyDelete
+	KeyInsert
+	KeyF1
+	KeyF2
+	KeyF3
+	KeyF4
+	KeyF5
+	KeyF6
+	KeyF7
+	KeyF8
+	KeyF9
+	KeyF10
+	KeyF11
+	KeyF12
+	KeyMouse
+	KeyResize
+	KeyPaste
 )
 
+// Modifier is a bitmask of the modifier keys held during an event
+type Modifier uint8
+
+const (
+	ModShift Modifier = 1 << iota
+	ModAlt
+	ModCtrl
+)
+
+type MouseButton int
+
+const (
+	MouseNone MouseButton = iota
+	MouseLeft
+	MouseMiddle
+	MouseRight
+	MouseWheelUp
+	MouseWheelDown
+)
+
+type MouseAction int
+
+const (
+	MousePress MouseAction = iota
+	MouseRelease
+	MouseMotion
+)
+
+// MouseEvent describes a mouse report. X and Y are zero-based cell
+// coordinates; Clicks counts consecutive presses of the same button on the
+// same cell, so a double click has Clicks == 2.
+type MouseEvent struct {
+	Button MouseButton
+	Action MouseAction
+	X      int
+	Y      int
+	Clicks int
+}
+
+// doubleClickInterval is the longest pause between presses that still
+// counts as a multi-click
+const doubleClickInterval = 400 * time.Millisecond
+
+// EscapeTimeout is how long an ESC at the end of the input waits for the
+// rest of a sequence before it is taken as the Esc key
+const EscapeTimeout = 40 * time.Millisecond
+
+// InputEvent is a single decoded input. Text holds the pasted content of
+// KeyPaste events, with line endings normalized to "\n".
 type InputEvent struct {
-	Key  Key
-	Rune rune
-	Raw  []byte
+	Key   Key
+	Rune  rune
+	Mod   Modifier
+	Mouse MouseEvent
+	Text  string
+	Raw   []byte
+}
+
+// Printable reports whether the event carries a character that should be
+// inserted as text, as opposed to a control or Alt chord.
+func (e *InputEvent) Printable() bool {
+	return e.Rune != 0 && e.Mod&(ModCtrl|ModAlt) == 0
 }
 
+// SingleLine flattens pasted text for a single-line field: trailing line
+// breaks are dropped, inner line breaks and tabs become spaces and other
+// control characters are removed.
+func SingleLine(text string) string {
+	text = strings.TrimRight(text, "\n")
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\t':
+			return ' '
+		case r < 32 || r == 127:
+			return -1
+		}
+		return r
+	}, text)
+}
+
+var stdinDecoder = &Decoder{}
+
+// ReadInput reads the next event from standard input
 func ReadInput() (*InputEvent, error) {
-	buf := make([]byte, 256)
-	n, err := os.Stdin.Read(buf)
-	if err != nil {
+	return readInput(os.Stdin, stdinDecoder)
+}
+
+// ReadInput reads the next event from the renderer's input stream. While
+// the renderer is initialized, a KeyResize event is returned after the
+// terminal changes size; the new size is already reflected by Width and
+// Height and the next frame is redrawn in full.
+func (r *Renderer) ReadInput() (*InputEvent, error) {
+	return r.ReadInputContext(context.Background())
+}
+
+// ReadInputContext is like ReadInput but gives up when ctx is done,
+// returning ctx.Err(). Only an initialized renderer can be interrupted
+// while waiting; otherwise ctx is checked before reading.
+func (r *Renderer) ReadInputContext(ctx context.Context) (*InputEvent, error) {
+	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
+	if r.events == nil {
+		return readInput(r.in, &r.decoder)
+	}
+	if r.inputErr != nil {
+		return nil, r.inputErr
+	}
+	if r.terminated.Load() {
+		return nil, ErrTerminated
+	}
 
-	event := &InputEvent{
-		Raw: buf[:n],
-	}
-
-	if n == 1 {
-		switch buf[0] {
-		case '\r', '\n':
-			event.Key = KeyEnter
-		case '\t':
-			event.Key = KeyTab
-		case 127, '\b':
-			event.Key = KeyBackspace
-		case 27:
-			event.Key = KeyEscape
-		case ' ':
-			event.Key = KeySpace
-		case 3:
-			event.Key = KeyCtrlC
-		case 4:
-			event.Key = KeyCtrlD
-		default:
-			if buf[0] >= 32 && buf[0] < 127 {
-				event.Rune = rune(buf[0])
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-r.killed:
+			return nil, ErrTerminated
+		case res, ok := <-r.events:
+			if !ok {
+				res = inputResult{err: r.input.err}
 			}
-		}
-	} else if n > 2 && buf[0] == 27 && buf[1] == '[' {
-		switch buf[2] {
-		case 'A':
-			event.Key = KeyArrowUp
-		case 'B':
-			event.Key = KeyArrowDown
-		case 'C':
-			event.Key = KeyArrowRight
-		case 'D':
-			event.Key = KeyArrowLeft
-		case 'H':
-			event.Key = KeyHome
-		case 'F':
-			event.Key = KeyEnd
-		case '3':
-			if n > 3 && buf[3] == '~' {
-				event.Key = KeyDelete
+			if res.err != nil {
+				// The reader has stopped; report the error from now on
+				r.inputErr = res.err
 			}
-		case '5':
-			if n > 3 && buf[3] == '~' {
-				event.Key = KeyPageUp
+			if r.isSuspendKey(res.event) {
+				// Suspend returns once the process is continued, and the
+				// next iteration reports the redraw
+				Suspend()
+				continue
 			}
-		case '6':
-			if n > 3 && buf[3] == '~' {
-				event.Key = KeyPageDown
+			return res.event, res.err
+		case <-r.continued:
+			// Whatever the shell printed meanwhile is below the inline
+			// region, so start a new one at the cursor
+			if r.inlineLines != nil {
+				r.inlineLines = r.inlineLines[:0]
+				r.inlineRow = 0
 			}
+			r.updateDimensions()
+			return &InputEvent{Key: KeyResize}, nil
+		case <-r.resized:
+			r.updateDimensions()
+			return &InputEvent{Key: KeyResize}, nil
 		}
 	}
+}
 
-	return event, nil
+func readInput(in io.Reader, d *Decoder) (*InputEvent, error) {
+	buf := make([]byte, 256)
+	for {
+		if event, ok := d.Next(); ok {
+			return event, nil
+		}
+
+		n, err := in.Read(buf)
+		if n > 0 {
+			d.Feed(buf[:n])
+			// A blocking read cannot wait for the rest of a sequence, so an
+			// ESC at the end is the Esc key right away
+			d.Flush()
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Decoder splits a raw terminal byte stream into input events. Sequences
+// that are cut between two reads are kept until the rest arrives. An ESC at
+// the end of the input is kept too, since it may start a sequence; Flush
+// takes it as the Esc key once EscapeTimeout passed without more input.
+type Decoder struct {
+	pending []byte
+	events  []*InputEvent
+
+	lastClick     MouseEvent
+	lastClickTime time.Time
+}
+
+// Feed appends bytes read from the terminal and decodes every complete
+// event they contain.
+func (d *Decoder) Feed(data []byte) {
+	d.pending = append(d.pending, data...)
+	d.decode(false)
+}
+
+// Flush decodes an ESC held back at the end of the input as the Esc key,
+// and ESC [ or ESC O as Alt+[ or Alt+O. Sequences cut in the middle are
+// still kept for the next Feed.
+func (d *Decoder) Flush() {
+	d.decode(true)
+}
+
+// Waiting reports whether an ESC at the end of the input is held back for
+// Flush
+func (d *Decoder) Waiting() bool {
+	if len(d.pending) == 0 {
+		return false
+	}
+	_, n := decodeEvent(d.pending, true)
+	return n > 0
+}
+
+// decode decodes the complete events at the start of d.pending. At the end
+// of the input, a trailing ESC is complete too.
+func (d *Decoder) decode(end bool) {
+	for len(d.pending) > 0 {
+		event, n := decodeEvent(d.pending, end)
+		if n == 0 {
+			break
+		}
+		event.Raw = append([]byte(nil), d.pending[:n]...)
+		if event.Key == KeyMouse {
+			d.countClicks(&event.Mouse)
+		}
+		d.events = append(d.events, event)
+		d.pending = d.pending[n:]
+	}
+}
+
+// countClicks detects repeated presses of the same button on the same cell
+func (d *Decoder) countClicks(m *MouseEvent) {
+	if m.Action != MousePress || m.Button == MouseWheelUp || m.Button == MouseWheelDown {
+		return
+	}
+
+	now := time.Now()
+	last := d.lastClick
+	if last.Button == m.Button && last.X == m.X && last.Y == m.Y &&
+		now.Sub(d.lastClickTime) <= doubleClickInterval {
+		m.Clicks = last.Clicks + 1
+	} else {
+		m.Clicks = 1
+	}
+	d.lastClick = *m
+	d.lastClickTime = now
+}
+
+// Next returns the oldest decoded event, if any
+func (d *Decoder) Next() (*InputEvent, bool) {
+	if len(d.events) == 0 {
+		return nil, false
+	}
+	event := d.events[0]
+	d.events = d.events[1:]
+	return event, true
+}
+
+// decodeEvent decodes the event at the start of buf and returns it with the
+// number of bytes consumed. A count of zero means buf ends in the middle of
+// a sequence. end tells whether no more input follows buf for now.
+func decodeEvent(buf []byte, end bool) (*InputEvent, int) {
+	b := buf[0]
+
+	switch {
+	case b == 27:
+		return decodeEscape(buf, end)
+	case b == '\r' || b == '\n':
+		return &InputEvent{Key: KeyEnter}, 1
+	case b == '\t':
+		return &InputEvent{Key: KeyTab}, 1
+	case b == 127 || b == '\b':
+		return &InputEvent{Key: KeyBackspace}, 1
+	case b == ' ':
+		return &InputEvent{Key: KeySpace, Rune: ' '}, 1
+	case b == 0:
+		return &InputEvent{Key: KeySpace, Mod: ModCtrl}, 1
+	case b == 3:
+		return &InputEvent{Key: KeyCtrlC}, 1
+	case b == 4:
+		return &InputEvent{Key: KeyCtrlD}, 1
+	case b < 27:
+		return &InputEvent{Rune: rune('a' + b - 1), Mod: ModCtrl}, 1
+	case b < 32:
+		return &InputEvent{Rune: rune(b + 64), Mod: ModCtrl}, 1
+	case b < utf8.RuneSelf:
+		return &InputEvent{Rune: rune(b)}, 1
+	}
+
+	if !utf8.FullRune(buf) {
+		return nil, 0
+	}
+	r, size := utf8.DecodeRune(buf)
+	if r == utf8.RuneError {
+		return &InputEvent{Key: KeyUnknown}, size
+	}
+	return &InputEvent{Rune: r}, size
+}
+
+// decodeEscape decodes input starting with ESC. An ESC (or `ESC [`,
+// `ESC O`) at the very end of the input may be the start of a sequence
+// whose rest is still on its way, so it is only taken as a key press of its
+// own once end says that no more input followed.
+func decodeEscape(buf []byte, end bool) (*InputEvent, int) {
+	if bytes.HasPrefix(buf, pasteStart) {
+		return decodePaste(buf)
+	}
+	if len(buf) == 1 {
+		if !end {
+			return nil, 0
+		}
+		return &InputEvent{Key: KeyEscape}, 1
+	}
+
+	switch buf[1] {
+	case '[':
+		if len(buf) > 2 {
+			return decodeCSI(buf)
+		}
+		if !end {
+			return nil, 0
+		}
+	case 'O':
+		if len(buf) > 2 {
+			return decodeSS3(buf[2]), 3
+		}
+		if !end {
+			return nil, 0
+		}
+	}
+
+	// ESC followed by another key is how terminals report Alt
+	event, n := decodeEvent(buf[1:], end)
+	if n == 0 {
+		return nil, 0
+	}
+	event.Mod |= ModAlt
+	return event, n + 1
+}
+
+var (
+	pasteStart = []byte("\033[200~")
+	pasteEnd   = []byte("\033[201~")
+)
+
+// decodePaste collects everything between the bracketed paste markers into
+// a single event, waiting for more input until the end marker arrives.
+func decodePaste(buf []byte) (*InputEvent, int) {
+	body := buf[len(pasteStart):]
+	end := bytes.Index(body, pasteEnd)
+	if end < 0 {
+		return nil, 0
+	}
+
+	text := strings.ReplaceAll(string(body[:end]), "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	return &InputEvent{Key: KeyPaste, Text: text}, len(pasteStart) + end + len(pasteEnd)
+}
+
+var ss3Keys = map[byte]Key{
+	'A': KeyArrowUp,
+	'B': KeyArrowDown,
+	'C': KeyArrowRight,
+	'D': KeyArrowLeft,
+	'H': KeyHome,
+	'F': KeyEnd,
+	'P': KeyF1,
+	'Q': KeyF2,
+	'R': KeyF3,
+	'S': KeyF4,
+}
+
+func decodeSS3(b byte) *InputEvent {
+	return &InputEvent{Key: ss3Keys[b]}
+}
+
+var tildeKeys = map[int]Key{
+	1:  KeyHome,
+	2:  KeyInsert,
+	3:  KeyDelete,
+	4:  KeyEnd,
+	5:  KeyPageUp,
+	6:  KeyPageDown,
+	7:  KeyHome,
+	8:  KeyEnd,
+	11: KeyF1,
+	12: KeyF2,
+	13: KeyF3,
+	14: KeyF4,
+	15: KeyF5,
+	17: KeyF6,
+	18: KeyF7,
+	19: KeyF8,
+	20: KeyF9,
+	21: KeyF10,
+	23: KeyF11,
+	24: KeyF12,
+}
+
+// decodeCSI decodes an `ESC [` sequence: parameter bytes, intermediate
+// bytes and a final byte.
+func decodeCSI(buf []byte) (*InputEvent, int) {
+	end := 2
+	for end < len(buf) && (buf[end] < 0x40 || buf[end] > 0x7e) {
+		if buf[end] < 0x20 {
+			// Not a valid sequence; report the bytes seen so far
+			return &InputEvent{Key: KeyUnknown}, end
+		}
+		end++
+	}
+	if end == len(buf) {
+		return nil, 0
+	}
+
+	params := strings.Split(string(buf[2:end]), ";")
+	final := buf[end]
+	n := end + 1
+
+	if strings.HasPrefix(params[0], "<") && (final == 'M' || final == 'm') {
+		return decodeMouse(params, final), n
+	}
+
+	event := &InputEvent{}
+	if len(params) > 1 {
+		event.Mod = parseModifier(params[1])
+	}
+
+	switch final {
+	case 'Z':
+		event.Key = KeyTab
+		event.Mod |= ModShift
+	case '~':
+		code, _ := strconv.Atoi(params[0])
+		event.Key = tildeKeys[code]
+	default:
+		event.Key = ss3Keys[final]
+	}
+	return event, n
+}
+
+// decodeMouse decodes an SGR (1006) mouse report: `ESC [ < b ; x ; y M`
+// for presses and motion, with a trailing `m` for releases.
+func decodeMouse(params []string, final byte) *InputEvent {
+	event := &InputEvent{Key: KeyMouse}
+	if len(params) != 3 {
+		event.Key = KeyUnknown
+		return event
+	}
+
+	code, _ := strconv.Atoi(strings.TrimPrefix(params[0], "<"))
+	x, _ := strconv.Atoi(params[1])
+	y, _ := strconv.Atoi(params[2])
+	event.Mouse.X = x - 1
+	event.Mouse.Y = y - 1
+
+	if code&4 != 0 {
+		event.Mod |= ModShift
+	}
+	if code&8 != 0 {
+		event.Mod |= ModAlt
+	}
+	if code&16 != 0 {
+		event.Mod |= ModCtrl
+	}
+
+	switch {
+	case code&64 != 0:
+		if code&1 == 0 {
+			event.Mouse.Button = MouseWheelUp
+		} else {
+			event.Mouse.Button = MouseWheelDown
+		}
+	case code&3 == 0:
+		event.Mouse.Button = MouseLeft
+	case code&3 == 1:
+		event.Mouse.Button = MouseMiddle
+	case code&3 == 2:
+		event.Mouse.Button = MouseRight
+	}
+
+	switch {
+	case final == 'm':
+		event.Mouse.Action = MouseRelease
+	case code&32 != 0:
+		event.Mouse.Action = MouseMotion
+	}
+	return event
+}
+
+// parseModifier converts the xterm modifier parameter (1 + bitmask)
+func parseModifier(param string) Modifier {
+	value, err := strconv.Atoi(param)
+	if err != nil || value < 2 {
+		return 0
+	}
+	bits := value - 1
+
+	var mod Modifier
+	if bits&1 != 0 {
+		mod |= ModShift
+	}
+	if bits&(2|8) != 0 {
+		mod |= ModAlt
+	}
+	if bits&4 != 0 {
+		mod |= ModCtrl
+	}
+	return mod
+}
+
+func (m Modifier) String() string {
+	var parts []string
+	if m&ModCtrl != 0 {
+		parts = append(parts, "Ctrl")
+	}
+	if m&ModAlt != 0 {
+		parts = append(parts, "Alt")
+	}
+	if m&ModShift != 0 {
+		parts = append(parts, "Shift")
+	}
+	return strings.Join(parts, "+")
 }
 
 func (k Key) String() string {
@@ -131,7 +601,18 @@ func (k Key) String() string {
 		return "PgDn"
 	case KeyDelete:
 		return "Delete"
+	case KeyInsert:
+		return "Insert"
+	case KeyMouse:
+		return "Mouse"
+	case KeyResize:
+		return "Resize"
+	case KeyPaste:
+		return "Paste"
 	default:
+		if k >= KeyF1 && k <= KeyF12 {
+			return fmt.Sprintf("F%d", k-KeyF1+1)
+		}
 		return fmt.Sprintf("Key(%d)", k)
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,242 @@
+package renderer_test
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vynazevedo/termx/renderer"
+)
+
+// decodeAll feeds chunks to a new decoder, flushes it as when no more input
+// arrives, and returns the events it decoded without their raw bytes
+func decodeAll(chunks ...string) []renderer.InputEvent {
+	var d renderer.Decoder
+	var events []renderer.InputEvent
+	for i := 0; i <= len(chunks); i++ {
+		if i < len(chunks) {
+			d.Feed([]byte(chunks[i]))
+		} else {
+			d.Flush()
+		}
+		for {
+			event, ok := d.Next()
+			if !ok {
+				break
+			}
+			event.Raw = nil
+			event.Mouse.Clicks = 0
+			events = append(events, *event)
+		}
+	}
+	return events
+}
+
+func TestDecoder(t *testing.T) {
+	type ev = renderer.InputEvent
+	tests := []struct {
+		name string
+		in   string
+		want []ev
+	}{
+		{"letters", "aZ", []ev{{Rune: 'a'}, {Rune: 'Z'}}},
+		{"utf-8", "ñ日", []ev{{Rune: 'ñ'}, {Rune: '日'}}},
+		{"enter", "\r\n", []ev{{Key: renderer.KeyEnter}, {Key: renderer.KeyEnter}}},
+		{"tab and backspace", "\t\x7f\b", []ev{{Key: renderer.KeyTab}, {Key: renderer.KeyBackspace}, {Key: renderer.KeyBackspace}}},
+		{"space", " ", []ev{{Key: renderer.KeySpace, Rune: ' '}}},
+		{"ctrl keys", "\x03\x04\x01\x1a", []ev{
+			{Key: renderer.KeyCtrlC},
+			{Key: renderer.KeyCtrlD},
+			{Rune: 'a', Mod: renderer.ModCtrl},
+			{Rune: 'z', Mod: renderer.ModCtrl},
+		}},
+		{"lone escape", "\033", []ev{{Key: renderer.KeyEscape}}},
+		{"escape at the end", "a\033\033", []ev{{Rune: 'a'}, {Key: renderer.KeyEscape, Mod: renderer.ModAlt}}},
+		{"alt bracket at the end", "\033[", []ev{{Rune: '[', Mod: renderer.ModAlt}}},
+		{"alt letter", "\033x", []ev{{Rune: 'x', Mod: renderer.ModAlt}}},
+		{"arrows", "\033[A\033[B\033[C\033[D", []ev{
+			{Key: renderer.KeyArrowUp},
+			{Key: renderer.KeyArrowDown},
+			{Key: renderer.KeyArrowRight},
+			{Key: renderer.KeyArrowLeft},
+		}},
+		{"ss3 keys", "\033OA\033OH\033OP", []ev{{Key: renderer.KeyArrowUp}, {Key: renderer.KeyHome}, {Key: renderer.KeyF1}}},
+		{"tilde keys", "\033[3~\033[5~\033[6~\033[15~\033[24~", []ev{
+			{Key: renderer.KeyDelete},
+			{Key: renderer.KeyPageUp},
+			{Key: renderer.KeyPageDown},
+			{Key: renderer.KeyF5},
+			{Key: renderer.KeyF12},
+		}},
+		{"modifiers", "\033[1;5C\033[1;2A\033[1;3D\033[3;6~", []ev{
+			{Key: renderer.KeyArrowRight, Mod: renderer.ModCtrl},
+			{Key: renderer.KeyArrowUp, Mod: renderer.ModShift},
+			{Key: renderer.KeyArrowLeft, Mod: renderer.ModAlt},
+			{Key: renderer.KeyDelete, Mod: renderer.ModCtrl | renderer.ModShift},
+		}},
+		{"shift tab", "\033[Z", []ev{{Key: renderer.KeyTab, Mod: renderer.ModShift}}},
+		{"mouse press and release", "\033[<0;5;3M\033[<0;5;3m", []ev{
+			{Key: renderer.KeyMouse, Mouse: renderer.MouseEvent{Button: renderer.MouseLeft, X: 4, Y: 2}},
+			{Key: renderer.KeyMouse, Mouse: renderer.MouseEvent{Button: renderer.MouseLeft, Action: renderer.MouseRelease, X: 4, Y: 2}},
+		}},
+		{"mouse wheel and motion", "\033[<64;1;1M\033[<65;1;1M\033[<34;2;2M", []ev{
+			{Key: renderer.KeyMouse, Mouse: renderer.MouseEvent{Button: renderer.MouseWheelUp}},
+			{Key: renderer.KeyMouse, Mouse: renderer.MouseEvent{Button: renderer.MouseWheelDown}},
+			{Key: renderer.KeyMouse, Mouse: renderer.MouseEvent{Button: renderer.MouseRight, Action: renderer.MouseMotion, X: 1, Y: 1}},
+		}},
+		{"mouse with ctrl", "\033[<16;1;1M", []ev{
+			{Key: renderer.KeyMouse, Mod: renderer.ModCtrl, Mouse: renderer.MouseEvent{Button: renderer.MouseLeft}},
+		}},
+		{"paste", "\033[200~line 1\r\nline 2\033[201~x", []ev{
+			{Key: renderer.KeyPaste, Text: "line 1\nline 2"},
+			{Rune: 'x'},
+		}},
+		{"invalid utf-8", "\xff", []ev{{Key: renderer.KeyUnknown}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decodeAll(tt.in)
+			if len(got) != len(tt.want) {
+				t.Fatalf("decoded %d events %+v, want %d %+v", len(got), got, len(tt.want), tt.want)
+			}
+			for i := range got {
+				if !reflect.DeepEqual(got[i], tt.want[i]) {
+					t.Errorf("event %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// Sequences split between reads are completed by the next read
+func TestDecoderSplitInput(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		want   renderer.InputEvent
+	}{
+		{"csi", []string{"\033[1;", "5A"}, renderer.InputEvent{Key: renderer.KeyArrowUp, Mod: renderer.ModCtrl}},
+		{"after escape", []string{"\033", "[B"}, renderer.InputEvent{Key: renderer.KeyArrowDown}},
+		{"after csi introducer", []string{"\033[", "C"}, renderer.InputEvent{Key: renderer.KeyArrowRight}},
+		{"ss3", []string{"\033O", "H"}, renderer.InputEvent{Key: renderer.KeyHome}},
+		{"utf-8", []string{"\xe6\x97", "\xa5"}, renderer.InputEvent{Rune: '日'}},
+		{"paste", []string{"\033[200~ab", "c\033[20", "1~"}, renderer.InputEvent{Key: renderer.KeyPaste, Text: "abc"}},
+		{"mouse", []string{"\033[<0;1", "0;2M"}, renderer.InputEvent{Key: renderer.KeyMouse, Mouse: renderer.MouseEvent{Button: renderer.MouseLeft, X: 9, Y: 1}}},
+	}
+	for _, tt := range tests {
+		got := decodeAll(tt.chunks...)
+		if len(got) != 1 || !reflect.DeepEqual(got[0], tt.want) {
+			t.Errorf("%s: decoded %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDecoderCountsClicks(t *testing.T) {
+	var d renderer.Decoder
+	d.Feed([]byte("\033[<0;3;3M\033[<0;3;3m\033[<0;3;3M\033[<0;4;3M"))
+
+	var clicks []int
+	for {
+		event, ok := d.Next()
+		if !ok {
+			break
+		}
+		if event.Mouse.Action == renderer.MousePress {
+			clicks = append(clicks, event.Mouse.Clicks)
+		}
+	}
+	if len(clicks) != 3 || clicks[0] != 1 || clicks[1] != 2 || clicks[2] != 1 {
+		t.Errorf("click counts = %v, want [1 2 1]", clicks)
+	}
+}
+
+func TestSingleLine(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"two\nlines\n", "two lines"},
+		{"tab\there", "tab here"},
+		{"bell\a", "bell"},
+	}
+	for _, tt := range tests {
+		if got := renderer.SingleLine(tt.in); got != tt.want {
+			t.Errorf("SingleLine(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// A read waiting for keys gives up when its context is done, and the keys
+// typed afterwards are still read in order
+func TestReadInputContextCancel(t *testing.T) {
+	in, w := io.Pipe()
+	defer w.Close()
+
+	r := renderer.NewWithIO(in, io.Discard, nil)
+	if err := r.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Restore()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := r.ReadInputContext(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("ReadInputContext() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	go io.WriteString(w, "x")
+	event, err := r.ReadInputContext(context.Background())
+	if err != nil || event.Rune != 'x' {
+		t.Fatalf("ReadInputContext() = %+v, %v, want 'x'", event, err)
+	}
+}
+
+// A line read gives up when its context is done even though the stream
+// never delivers a newline, and the bytes that arrive later start the next
+// line
+func TestReadLineContextCancel(t *testing.T) {
+	in, w := io.Pipe()
+	defer w.Close()
+	r := renderer.NewWithIO(in, io.Discard, nil)
+
+	go io.WriteString(w, "par")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := r.ReadLineContext(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("ReadLineContext() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	go io.WriteString(w, "next\n")
+	line, err := r.ReadLine()
+	if err != nil || line != "next" {
+		t.Fatalf("ReadLine() = %q, %v, want %q", line, err, "next")
+	}
+}
+
+// An ESC at the end of a read waits for the rest of a sequence that is
+// split between reads, and is the Esc key when nothing follows in time
+func TestReadInputEscapeTimeout(t *testing.T) {
+	in, w := io.Pipe()
+	defer w.Close()
+
+	r := renderer.NewWithIO(in, io.Discard, nil)
+	if err := r.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Restore()
+
+	go func() {
+		io.WriteString(w, "\033")
+		time.Sleep(renderer.EscapeTimeout / 4)
+		io.WriteString(w, "[A")
+		io.WriteString(w, "\033")
+	}()
+	for _, want := range []renderer.Key{renderer.KeyArrowUp, renderer.KeyEscape} {
+		event, err := r.ReadInput()
+		if err != nil || event.Key != want || event.Mod != 0 {
+			t.Fatalf("ReadInput() = %+v, %v, want key %v", event, err, want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package renderer
+
+import (
+	"context"
+	"io"
+	"strings"
+)
+
+// lineByte is the result of reading one byte of a line
+type lineByte struct {
+	b   byte
+	n   int
+	err error
+}
+
+// ReadLine reads one line from the input stream and returns it without the
+// line ending. It is meant for prompts running without a terminal, where
+// input is piped in. Bytes are read one at a time so that the answers to
+// later prompts stay in the stream. At the end of the input a final line
+// without a line ending is returned, and io.EOF after that.
+func (r *Renderer) ReadLine() (string, error) {
+	return r.ReadLineContext(context.Background())
+}
+
+// ReadLineContext is like ReadLine but gives up when ctx is done, returning
+// ctx.Err(). A read still waiting for the stream is kept, and the byte it
+// gets starts the next line, so that no input is lost.
+func (r *Renderer) ReadLineContext(ctx context.Context) (string, error) {
+	var line []byte
+	for {
+		res, err := r.readLineByte(ctx)
+		if err != nil {
+			return "", err
+		}
+		if res.n > 0 {
+			if res.b == '\n' {
+				return strings.TrimSuffix(string(line), "\r"), nil
+			}
+			line = append(line, res.b)
+		}
+		if res.err != nil {
+			if res.err == io.EOF && len(line) > 0 {
+				return strings.TrimSuffix(string(line), "\r"), nil
+			}
+			return "", res.err
+		}
+	}
+}
+
+// readLineByte reads the next byte of the input stream. The read runs in
+// the background when ctx can be done, and stays pending in r.lineRead when
+// ctx is done first.
+func (r *Renderer) readLineByte(ctx context.Context) (lineByte, error) {
+	if err := ctx.Err(); err != nil {
+		return lineByte{}, err
+	}
+	if r.lineRead == nil && ctx.Done() == nil {
+		buf := make([]byte, 1)
+		n, err := r.in.Read(buf)
+		return lineByte{b: buf[0], n: n, err: err}, nil
+	}
+
+	if r.lineRead == nil {
+		read := make(chan lineByte, 1)
+		r.lineRead = read
+		go func() {
+			buf := make([]byte, 1)
+			n, err := r.in.Read(buf)
+			read <- lineByte{b: buf[0], n: n, err: err}
+		}()
+	}
+	select {
+	case res := <-r.lineRead:
+		r.lineRead = nil
+		return res, nil
+	case <-ctx.Done():
+		return lineByte{}, ctx.Err()
+	}
+}
@@ -0,0 +1,75 @@
+package renderer
+
+import (
+	"io"
+	"reflect"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// terminals keeps the last initialized renderer of each output stream. A
+// renderer initialized while another one holds the same output is nested
+// in it, as for a Select run from a Menu action: it leaves the raw mode,
+// the terminal modes and the alternate screen of the outer renderer in
+// place and gives them back when it is restored, instead of handing the
+// terminal to the shell.
+var terminals struct {
+	sync.Mutex
+	top map[io.Writer]*Renderer
+}
+
+// pushTerminal makes r the last renderer of its output and sets r.outer to
+// the renderer it is nested in, if any
+func (r *Renderer) pushTerminal() {
+	r.outer = nil
+	if !reflect.TypeOf(r.out).Comparable() {
+		return
+	}
+	terminals.Lock()
+	defer terminals.Unlock()
+
+	if terminals.top == nil {
+		terminals.top = make(map[io.Writer]*Renderer)
+	}
+	r.outer = terminals.top[r.out]
+	terminals.top[r.out] = r
+}
+
+// popTerminal hands the output back to the renderer r was nested in and
+// returns it, or nil when r was not nested or the outer renderer was
+// restored first
+func (r *Renderer) popTerminal() *Renderer {
+	outer := r.outer
+	r.outer = nil
+	if !reflect.TypeOf(r.out).Comparable() {
+		return nil
+	}
+	terminals.Lock()
+	defer terminals.Unlock()
+
+	if outer != nil && outer.depth == 0 {
+		outer = nil
+	}
+	if terminals.top[r.out] == r {
+		if outer != nil {
+			terminals.top[r.out] = outer
+		} else {
+			delete(terminals.top, r.out)
+		}
+	}
+	return outer
+}
+
+// sharedState returns the state the renderer r is nested in restores the
+// terminal to, when both read from the terminal with file descriptor fd.
+// That terminal is in raw mode already.
+func (r *Renderer) sharedState(fd uintptr) *term.State {
+	if r.outer == nil || r.outer.oldState == nil {
+		return nil
+	}
+	if f, ok := r.outer.in.(fileDescriptor); !ok || f.Fd() != fd {
+		return nil
+	}
+	return r.outer.oldState
+}
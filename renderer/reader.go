@@ -0,0 +1,281 @@
+package renderer
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// errCanceled is returned by a cancelReader after Cancel was called
+var errCanceled = errors.New("read canceled")
+
+// cancelReader is an input stream whose blocked Read can be interrupted, so
+// that a renderer can stop reading without swallowing the next keystroke.
+type cancelReader interface {
+	io.Reader
+	// Cancel makes pending and future reads return errCanceled. It reports
+	// whether a pending read is guaranteed to return promptly.
+	Cancel() bool
+	// Close releases the resources of a canceled reader once its last
+	// Read has returned.
+	Close() error
+}
+
+// plainReader wraps streams that cannot be interrupted. A blocked read
+// returns only when the stream produces data or is closed.
+type plainReader struct {
+	in       io.Reader
+	canceled chan struct{}
+}
+
+func newPlainReader(in io.Reader) *plainReader {
+	return &plainReader{in: in, canceled: make(chan struct{})}
+}
+
+func (p *plainReader) Read(buf []byte) (int, error) {
+	select {
+	case <-p.canceled:
+		return 0, errCanceled
+	default:
+	}
+	return p.in.Read(buf)
+}
+
+func (p *plainReader) Cancel() bool {
+	close(p.canceled)
+	return false
+}
+
+func (p *plainReader) Close() error {
+	return nil
+}
+
+type inputResult struct {
+	event *InputEvent
+	err   error
+}
+
+// inputs holds the background reader of each input stream, shared by the
+// renderers that read from it. A component run from inside another one then
+// gets the keys in the order they were typed instead of racing the outer
+// component's reader for them.
+var inputs struct {
+	sync.Mutex
+	readers map[io.Reader]*inputReader
+}
+
+// inputReader reads and decodes an input stream in the background
+type inputReader struct {
+	key    io.Reader // in, or nil for streams that cannot be map keys
+	events chan inputResult
+	quit   chan struct{}
+	done   chan struct{}
+	reader cancelReader
+	users  int
+	err    error // why reading stopped, set before events is closed
+}
+
+// startInput begins reading and decoding the input stream in the
+// background, or joins the reader another renderer already runs on it.
+// Decoded events, errors and resize notifications are delivered through
+// r.events.
+func (r *Renderer) startInput() {
+	r.watchResize()
+	r.input = acquireInput(r.in, r.input)
+	r.events = r.input.events
+	r.inputErr = nil
+}
+
+// stopInput leaves the background reader started by startInput, which
+// stops once no renderer uses it
+func (r *Renderer) stopInput() {
+	r.stopResize()
+	if r.input == nil {
+		return
+	}
+	if releaseInput(r.input) {
+		// The reader of an uninterruptible stream is still running; reads
+		// after Restore keep going through it
+		return
+	}
+	r.input = nil
+	r.events = nil
+	r.inputErr = nil
+}
+
+// acquireInput returns the reader already running on in, or kept, the one a
+// renderer held on to for its uninterruptible stream, or starts a new one
+func acquireInput(in io.Reader, kept *inputReader) *inputReader {
+	inputs.Lock()
+	defer inputs.Unlock()
+
+	var key io.Reader
+	if reflect.TypeOf(in).Comparable() {
+		key = in
+		if ir, ok := inputs.readers[key]; ok {
+			kept = ir
+		}
+	}
+	if kept != nil && !kept.exhausted() {
+		kept.users++
+		return kept
+	}
+
+	ir := newInputReader(in)
+	ir.key = key
+	ir.users = 1
+	if key != nil {
+		if inputs.readers == nil {
+			inputs.readers = make(map[io.Reader]*inputReader)
+		}
+		inputs.readers[key] = ir
+	}
+	return ir
+}
+
+// releaseInput gives up one use of ir and stops it when it was the last.
+// It reports whether the reader keeps running.
+func releaseInput(ir *inputReader) bool {
+	inputs.Lock()
+	defer inputs.Unlock()
+
+	ir.users--
+	if ir.users > 0 {
+		return true
+	}
+	if _, ok := ir.reader.(*plainReader); ok && !ir.exhausted() {
+		// A blocked read on this stream cannot be interrupted. Keep the
+		// reader for the next session instead of leaving it to swallow a
+		// key, so that input typed ahead is not lost either.
+		return true
+	}
+
+	if ir.key != nil && inputs.readers[ir.key] == ir {
+		delete(inputs.readers, ir.key)
+	}
+	close(ir.quit)
+	if ir.reader.Cancel() {
+		<-ir.done
+		ir.reader.Close()
+	}
+	return false
+}
+
+// newInputReader starts reading in. Events are sent on events until quit is
+// closed; when reading fails, err is set and events is closed.
+//
+// One goroutine reads and another decodes, so that an ESC at the end of a
+// read can be taken as the Esc key once EscapeTimeout passed while the next
+// read is still waiting.
+func newInputReader(in io.Reader) *inputReader {
+	ir := &inputReader{
+		events: make(chan inputResult, 16),
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+		reader: newCancelReader(in),
+	}
+
+	type chunk struct {
+		data []byte
+		err  error
+	}
+	chunks := make(chan chunk)
+	readDone := make(chan struct{})
+
+	go func() {
+		defer close(readDone)
+		buf := make([]byte, 256)
+		for {
+			n, err := ir.reader.Read(buf)
+			select {
+			case chunks <- chunk{data: append([]byte(nil), buf[:n]...), err: err}:
+			case <-ir.quit:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	go func() {
+		// The reader is only done once the reading goroutine returned
+		defer close(ir.done)
+		defer func() { <-readDone }()
+
+		var decoder Decoder
+		var escape <-chan time.Time
+		for {
+			select {
+			case c := <-chunks:
+				decoder.Feed(c.data)
+				if c.err != nil {
+					decoder.Flush()
+				}
+				if !ir.send(&decoder) {
+					return
+				}
+				if c.err != nil {
+					if c.err != errCanceled {
+						ir.err = c.err
+						close(ir.events)
+					}
+					return
+				}
+				escape = nil
+				if decoder.Waiting() {
+					escape = time.After(EscapeTimeout)
+				}
+			case <-escape:
+				escape = nil
+				decoder.Flush()
+				if !ir.send(&decoder) {
+					return
+				}
+			case <-ir.quit:
+				return
+			}
+		}
+	}()
+	return ir
+}
+
+// send delivers the events d decoded. It reports false when quit was closed
+// first.
+func (ir *inputReader) send(d *Decoder) bool {
+	for {
+		event, ok := d.Next()
+		if !ok {
+			return true
+		}
+		select {
+		case ir.events <- inputResult{event: event}:
+		case <-ir.quit:
+			return false
+		}
+	}
+}
+
+// exhausted reports whether the reader stopped reading and every event it
+// decoded was received
+func (ir *inputReader) exhausted() bool {
+	select {
+	case <-ir.done:
+		return len(ir.events) == 0
+	default:
+		return false
+	}
+}
+
+// NotifyResize makes the next ReadInput re-query the terminal size and
+// return a KeyResize event. Resizes of the local terminal are detected
+// automatically; this is meant for streams such as SSH channels that report
+// window changes out of band. It is safe to call from any goroutine.
+func (r *Renderer) NotifyResize() {
+	select {
+	case r.resized <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package renderer
+
+import "io"
+
+func newCancelReader(in io.Reader) cancelReader {
+	return newPlainReader(in)
+}
+
+// watchResize is a no-op on platforms without SIGWINCH; callers can still
+// report size changes through NotifyResize.
+func (r *Renderer) watchResize() {}
+
+func (r *Renderer) stopResize() {}
@@ -0,0 +1,51 @@
+package renderer_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/vynazevedo/termx/renderer"
+)
+
+// readRune reads the next event from r and fails unless it is the key want
+func readRune(t *testing.T, r *renderer.Renderer, want rune) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	event, err := r.ReadInputContext(ctx)
+	if err != nil {
+		t.Fatalf("reading %q: %v", want, err)
+	}
+	if event.Rune != want {
+		t.Fatalf("read %q, want %q", event.Rune, want)
+	}
+}
+
+// A renderer initialized while another one reads the same stream, as for a
+// component run from a menu action, gets the keys typed after it started
+// and hands the rest back to the outer renderer
+func TestNestedRenderersShareInput(t *testing.T) {
+	in, w := io.Pipe()
+	defer w.Close()
+	go io.WriteString(w, "abcd")
+
+	outer := renderer.NewWithIO(in, io.Discard, nil)
+	if err := outer.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer outer.Restore()
+	readRune(t, outer, 'a')
+
+	inner := renderer.NewWithIO(in, io.Discard, nil)
+	if err := inner.Init(); err != nil {
+		t.Fatal(err)
+	}
+	readRune(t, inner, 'b')
+	readRune(t, inner, 'c')
+	inner.Restore()
+
+	readRune(t, outer, 'd')
+}
@@ -0,0 +1,104 @@
+//go:build unix
+
+package renderer
+
+import (
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// pollReader reads from a file descriptor after polling it together with a
+// wake-up pipe, so that Cancel can interrupt a blocked read.
+type pollReader struct {
+	fd       int
+	wakeR    *os.File
+	wakeW    *os.File
+	canceled bool
+}
+
+func newCancelReader(in io.Reader) cancelReader {
+	f, ok := in.(fileDescriptor)
+	if !ok {
+		return newPlainReader(in)
+	}
+	wakeR, wakeW, err := os.Pipe()
+	if err != nil {
+		return newPlainReader(in)
+	}
+	return &pollReader{fd: int(f.Fd()), wakeR: wakeR, wakeW: wakeW}
+}
+
+func (p *pollReader) Read(buf []byte) (int, error) {
+	fds := []unix.PollFd{
+		{Fd: int32(p.fd), Events: unix.POLLIN},
+		{Fd: int32(p.wakeR.Fd()), Events: unix.POLLIN},
+	}
+	for {
+		_, err := unix.Poll(fds, -1)
+		if err == unix.EINTR {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		if fds[1].Revents != 0 {
+			return 0, errCanceled
+		}
+		n, err := unix.Read(p.fd, buf)
+		if err == unix.EINTR || err == unix.EAGAIN {
+			continue
+		}
+		if n == 0 && err == nil {
+			return 0, io.EOF
+		}
+		if n < 0 {
+			n = 0
+		}
+		return n, err
+	}
+}
+
+func (p *pollReader) Cancel() bool {
+	if !p.canceled {
+		p.canceled = true
+		p.wakeW.Close()
+	}
+	return true
+}
+
+func (p *pollReader) Close() error {
+	return p.wakeR.Close()
+}
+
+// watchResize delivers a KeyResize event whenever the terminal is resized
+func (r *Renderer) watchResize() {
+	signals := make(chan os.Signal, 1)
+	stop := make(chan struct{})
+	signal.Notify(signals, syscall.SIGWINCH)
+	r.stopResizeWatch = func() {
+		signal.Stop(signals)
+		close(stop)
+	}
+
+	go func() {
+		for {
+			select {
+			case <-signals:
+				r.NotifyResize()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+func (r *Renderer) stopResize() {
+	if r.stopResizeWatch != nil {
+		r.stopResizeWatch()
+		r.stopResizeWatch = nil
+	}
+}
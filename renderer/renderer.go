@@ -1,55 +1,241 @@
 package renderer
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"runtime"
 	"strings"
+	"sync/atomic"
 
+	"github.com/vynazevedo/termx/colorprofile"
 	"golang.org/x/term"
 )
 
+// ErrNotTerminal is returned by Init when the input stream is a file or pipe
+// rather than a terminal. Prompts fall back to reading plain lines then.
+var ErrNotTerminal = errors.New("not running in a terminal")
+
+// SizeFunc reports the size of the terminal in cells
+type SizeFunc func() (width, height int, err error)
+
+// fileDescriptor is implemented by streams backed by an OS file, such as
+// os.Stdin or a pty
+type fileDescriptor interface {
+	Fd() uintptr
+}
+
 type Renderer struct {
 	width  int
 	height int
 	oldState *term.State
+
+	in    io.Reader
+	out   io.Writer
+	size  SizeFunc
+	profile colorprofile.Profile
+	depth int
+	mouse bool
+	line  int
+
+	altScreen   bool
+	inAltScreen bool
+	outer       *Renderer   // renderer this one is nested in, see terminals
+	repaint     atomic.Bool // set when a nested renderer drew over the screen
+
+	decoder  Decoder
+	lineRead chan lineByte // read of ReadLineContext left pending by ctx
+
+	input           *inputReader
+	events          chan inputResult
+	inputErr        error
+	resized         chan struct{}
+	continued       chan struct{}
+	killed          chan struct{} // signaled when a termination signal arrives
+	terminated      atomic.Bool
+	stopResizeWatch func()
+
+	front     *buffer
+	back      *buffer
+	cursorX   int
+	cursorY   int
+	cursorSet bool
+
+	// Lines of the inline region and the row of it the cursor is on
+	inlineLines []string
+	inlineRow   int
 }
 
 func New() *Renderer {
-	r := &Renderer{}
+	return NewWithIO(os.Stdin, os.Stdout, nil)
+}
+
+// NewWithIO creates a renderer that reads keys from in and draws to out.
+// When size is nil the dimensions are queried from out if it is a terminal.
+func NewWithIO(in io.Reader, out io.Writer, size SizeFunc) *Renderer {
+	if size == nil {
+		size = terminalSize(out)
+	}
+	r := &Renderer{
+		in:        in,
+		out:       out,
+		size:      size,
+		profile:   colorprofile.Detect(out),
+		resized:   make(chan struct{}, 1),
+		continued: make(chan struct{}, 1),
+		killed:    make(chan struct{}, 1),
+	}
 	r.updateDimensions()
 	return r
 }
 
+// terminalSize returns a SizeFunc that queries the terminal behind out,
+// falling back to 80x24 for streams that are not terminals.
+func terminalSize(out io.Writer) SizeFunc {
+	return func() (int, int, error) {
+		if f, ok := out.(fileDescriptor); ok {
+			width, height, err := term.GetSize(int(f.Fd()))
+			if err == nil {
+				return width, height, nil
+			}
+		}
+		return 80, 24, nil
+	}
+}
+
+// WithMouse enables mouse reporting while the renderer is initialized.
+// Clicks and wheel movements are then delivered as KeyMouse events.
+func (r *Renderer) WithMouse() *Renderer {
+	r.mouse = true
+	return r
+}
+
+// WithProfile overrides the color profile detected for the output stream
+func (r *Renderer) WithProfile(p colorprofile.Profile) *Renderer {
+	r.profile = p
+	return r
+}
+
+// Profile returns the color profile styles are converted to before output
+func (r *Renderer) Profile() colorprofile.Profile {
+	return r.profile
+}
+
+// filter converts the SGR sequences of text to the color profile of the output
+func (r *Renderer) filter(text string) string {
+	if r.profile == colorprofile.TrueColor || !strings.Contains(text, "\033") {
+		return text
+	}
+	var out strings.Builder
+	for _, tok := range Tokenize(text) {
+		if tok.Kind == TokenSGR {
+			out.WriteString(r.profile.Convert(tok.Value))
+		} else {
+			out.WriteString(tok.Value)
+		}
+	}
+	return out.String()
+}
+
+// Init puts the input stream in raw mode when it is a terminal. Streams that
+// are not backed by a file, such as an SSH channel, are used as they are.
+// Nested calls on the same renderer only start a new frame; a renderer
+// initialized while another one holds the same output shares its terminal
+// modes and input until it is restored.
 func (r *Renderer) Init() error {
-	if !term.IsTerminal(int(os.Stdin.Fd())) {
-		return fmt.Errorf("not running in a terminal")
+	if r.depth > 0 {
+		r.depth++
+		r.front = nil
+		r.Clear()
+		return nil
 	}
 
-	oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
-	if err != nil {
-		return err
+	if r.in == io.Reader(os.Stdin) && r.out == io.Writer(os.Stdout) {
+		// Ask for the background color once, for the styles to pick their
+		// colors, before the input is read for keys and takes the answer
+		colorprofile.Background()
+	}
+
+	r.pushTerminal()
+	if f, ok := r.in.(fileDescriptor); ok {
+		fd := int(f.Fd())
+		if !term.IsTerminal(fd) {
+			r.popTerminal()
+			return ErrNotTerminal
+		}
+
+		if state := r.sharedState(f.Fd()); state != nil {
+			// The outer renderer put the terminal in raw mode already;
+			// keep the state it goes back to, for RestoreAll
+			r.oldState = state
+		} else {
+			oldState, err := term.MakeRaw(fd)
+			if err != nil {
+				r.popTerminal()
+				return err
+			}
+			r.oldState = oldState
+		}
 	}
-	r.oldState = oldState
 
-	r.HideCursor()
+	r.depth = 1
+	r.terminated.Store(false)
+	select {
+	case <-r.killed:
+	default:
+	}
+	register(r)
+	if r.altScreen {
+		r.enterAltScreen()
+	}
+	r.startInput()
+	io.WriteString(r.out, r.setupSequence())
+	r.front = nil
 	r.Clear()
 	return nil
 }
 
 func (r *Renderer) Restore() error {
-	r.ShowCursor()
+	if r.depth == 0 {
+		// Never initialized: nothing to undo, and nothing to write into pipes
+		return nil
+	}
+	if r.depth > 1 {
+		r.depth--
+		return nil
+	}
+	r.depth = 0
+	unregister(r)
+	r.stopInput()
+	outer := r.popTerminal()
+	io.WriteString(r.out, r.resetSequence())
+	r.leaveAltScreen(outer)
+	if outer != nil {
+		// Give the terminal modes back to the outer renderer and make it
+		// redraw the screen this one drew over
+		io.WriteString(r.out, outer.setupSequence())
+		outer.repaint.Store(true)
+	}
 	if r.oldState != nil {
-		return term.Restore(int(os.Stdin.Fd()), r.oldState)
+		state := r.oldState
+		r.oldState = nil
+		if outer != nil && outer.oldState == state {
+			return nil
+		}
+		return term.Restore(int(r.in.(fileDescriptor).Fd()), state)
 	}
 	return nil
 }
 
 func (r *Renderer) updateDimensions() {
-	width, height, _ := term.GetSize(int(os.Stdout.Fd()))
+	width, height, _ := r.size()
 	r.width = width
 	r.height = height
+	r.back = newBuffer(width, height)
+	r.front = nil
+	r.invalidateInline()
 }
 
 func (r *Renderer) Width() int {
@@ -60,33 +246,90 @@ func (r *Renderer) Height() int {
 	return r.height
 }
 
+// Clear starts a new frame by blanking the back buffer. Nothing is written
+// to the terminal until Flush is called.
 func (r *Renderer) Clear() {
-	fmt.Print("\033[2J\033[H")
+	r.back.clear()
+	r.cursorSet = false
+}
+
+// Flush commits the back buffer to the terminal, writing only the cells that
+// changed since the previous frame.
+func (r *Renderer) Flush() {
+	var out strings.Builder
+
+	if r.repaint.Swap(false) {
+		r.front = nil
+	}
+	if r.front == nil {
+		out.WriteString("\033[2J\033[H")
+		r.front = newBuffer(r.back.width, r.back.height)
+	}
+
+	out.WriteString("\033[?25l")
+
+	curX, curY := -1, -1
+	style := ""
+	for y := 0; y < r.back.height; y++ {
+		for x := 0; x < r.back.width; x++ {
+			cell := r.back.cell(x, y)
+			if cell == r.front.cell(x, y) || cell.Width == 0 {
+				continue
+			}
+			if x != curX || y != curY {
+				out.WriteString(fmt.Sprintf("\033[%d;%dH", y+1, x+1))
+			}
+			if cell.Style != style {
+				out.WriteString(r.filter("\033[0m" + cell.Style))
+				style = cell.Style
+			}
+			out.WriteRune(cell.Rune)
+			out.WriteString(cell.Comb)
+			curX, curY = x+cell.Width, y
+		}
+	}
+	if style != "" {
+		out.WriteString(r.filter("\033[0m"))
+	}
+
+	if r.cursorSet {
+		out.WriteString(fmt.Sprintf("\033[%d;%dH\033[?25h", r.cursorY+1, r.cursorX+1))
+	}
+
+	r.front.copyFrom(r.back)
+	io.WriteString(r.out, out.String())
+}
+
+// SetCursor places the visible cursor at x, y once the frame is flushed.
+func (r *Renderer) SetCursor(x, y int) {
+	r.cursorX = x
+	r.cursorY = y
+	r.cursorSet = true
 }
 
 func (r *Renderer) ClearLine() {
-	fmt.Print("\033[2K")
+	io.WriteString(r.out, "\033[2K")
 }
 
 func (r *Renderer) MoveCursor(x, y int) {
-	fmt.Printf("\033[%d;%dH", y+1, x+1)
+	fmt.Fprintf(r.out, "\033[%d;%dH", y+1, x+1)
 }
 
 func (r *Renderer) HideCursor() {
-	fmt.Print("\033[?25l")
+	io.WriteString(r.out, "\033[?25l")
 }
 
 func (r *Renderer) ShowCursor() {
-	fmt.Print("\033[?25h")
+	io.WriteString(r.out, "\033[?25h")
 }
 
+// Print draws text into the back buffer at x, y.
 func (r *Renderer) Print(x, y int, text string) {
-	r.MoveCursor(x, y)
-	fmt.Print(text)
+	r.back.drawText(x, y, text, r.back.bounds())
 }
 
 func (r *Renderer) PrintCentered(y int, text string) {
-	x := (r.width - len(stripANSI(text))) / 2
+	x := (r.width - StringWidth(text)) / 2
 	if x < 0 {
 		x = 0
 	}
@@ -97,7 +340,7 @@ func (r *Renderer) Box(x, y, width, height int, title string) {
 	r.Print(x, y, "┌"+strings.Repeat("─", width-2)+"┐")
 	
 	if title != "" {
-		titleLen := len(stripANSI(title))
+		titleLen := StringWidth(title)
 		titleX := x + (width-titleLen-2)/2
 		r.Print(titleX, y, "┤"+title+"├")
 	}
@@ -109,39 +352,37 @@ func (r *Renderer) Box(x, y, width, height int, title string) {
 	r.Print(x, y+height-1, "└"+strings.Repeat("─", width-2)+"┘")
 }
 
-func stripANSI(text string) string {
-	var result strings.Builder
-	i := 0
-	for i < len(text) {
-		if text[i] == '\033' {
-			for i < len(text) && text[i] != 'm' {
-				i++
-			}
-			if i < len(text) {
-				i++
-			}
-		} else {
-			result.WriteByte(text[i])
-			i++
-		}
+// Write outputs text at the current cursor position. Line feeds are
+// expanded to CR LF while the terminal is in raw mode.
+func (r *Renderer) Write(text string) {
+	r.line += strings.Count(text, "\n")
+	if r.oldState != nil {
+		text = strings.ReplaceAll(text, "\n", "\r\n")
 	}
-	return result.String()
+	io.WriteString(r.out, r.filter(text))
 }
 
-func (r *Renderer) Write(text string) {
-	fmt.Print(text)
+// Line returns the number of lines written since the screen was last
+// cleared, which is the row the next Write starts on.
+func (r *Renderer) Line() int {
+	return r.line
+}
+
+// Printf formats according to a format specifier and writes the result
+func (r *Renderer) Printf(format string, args ...interface{}) {
+	r.Write(fmt.Sprintf(format, args...))
 }
 
 func (r *Renderer) WriteStyled(text, color string) {
-	fmt.Print(color + text + "\033[0m")
+	r.Write(color + text + "\033[0m")
 }
 
 func (r *Renderer) NewLine() {
-	fmt.Print("\n")
+	r.Write("\n")
 }
 
 func (r *Renderer) MoveCursorUp(lines int) {
-	fmt.Printf("\033[%dA", lines)
+	fmt.Fprintf(r.out, "\033[%dA", lines)
 }
 
 func (r *Renderer) Close() {
@@ -149,15 +390,19 @@ func (r *Renderer) Close() {
 }
 
 func (r *Renderer) ReadKey() (Key, error) {
-	event, err := ReadInput()
+	event, err := r.ReadInput()
 	if err != nil {
 		return KeyUnknown, err
 	}
 	return event.Key, nil
 }
 
+// ClearScreen erases the terminal immediately, bypassing the frame buffer.
 func (r *Renderer) ClearScreen() {
-	r.Clear()
+	io.WriteString(r.out, "\033[2J\033[H")
+	r.line = 0
+	r.back.clear()
+	r.front = newBuffer(r.back.width, r.back.height)
 }
 
 func ClearScreen() {
@@ -0,0 +1,125 @@
+package renderer_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/vynazevedo/termx/colorprofile"
+	"github.com/vynazevedo/termx/renderer"
+)
+
+func TestFlushWritesChangedCells(t *testing.T) {
+	var out bytes.Buffer
+	r := renderer.NewWithIO(strings.NewReader(""), &out, func() (int, int, error) {
+		return 10, 3, nil
+	}).WithProfile(colorprofile.TrueColor)
+
+	frames := []struct {
+		draw    func()
+		written []string
+		skipped []string
+	}{
+		{
+			draw:    func() { r.Print(0, 0, "hello"); r.Print(2, 1, "\033[1mworld\033[0m") },
+			written: []string{"\033[2J", "hello", "\033[0m\033[1mworld"},
+		},
+		{
+			draw:    func() { r.Print(0, 0, "hallo"); r.Print(2, 1, "\033[1mworld\033[0m") },
+			written: []string{"\033[1;2Ha"},
+			skipped: []string{"\033[2J", "llo", "world"},
+		},
+		{
+			draw:    func() { r.Print(0, 0, "hallo"); r.Print(2, 1, "\033[1mworld\033[0m") },
+			skipped: []string{"\033[2J", "\033[1;", "hallo", "world"},
+		},
+		{
+			draw:    func() { r.Print(0, 2, "日本") },
+			written: []string{"\033[1;1H     ", "\033[3;1H日本"},
+			skipped: []string{"\033[2J"},
+		},
+	}
+	for i, f := range frames {
+		out.Reset()
+		r.Clear()
+		f.draw()
+		r.Flush()
+
+		got := out.String()
+		for _, s := range f.written {
+			if !strings.Contains(got, s) {
+				t.Errorf("frame %d: output %q does not contain %q", i, got, s)
+			}
+		}
+		for _, s := range f.skipped {
+			if strings.Contains(got, s) {
+				t.Errorf("frame %d: output %q contains %q", i, got, s)
+			}
+		}
+	}
+}
+
+func TestNewWithIO(t *testing.T) {
+	var out bytes.Buffer
+	r := renderer.NewWithIO(strings.NewReader("\033[Bq"), &out, func() (int, int, error) {
+		return 30, 7, nil
+	})
+	if r.Width() != 30 || r.Height() != 7 {
+		t.Errorf("size = %dx%d, want 30x7 from the size function", r.Width(), r.Height())
+	}
+
+	event, err := r.ReadInput()
+	if err != nil {
+		t.Fatalf("ReadInput() error = %v", err)
+	}
+	if event.Key != renderer.KeyArrowDown {
+		t.Errorf("ReadInput() key = %v, want %v", event.Key, renderer.KeyArrowDown)
+	}
+
+	r.Printf("%d items", 3)
+	if got := out.String(); got != "3 items" {
+		t.Errorf("output = %q, want %q", got, "3 items")
+	}
+}
+
+// Streams that are not terminals get the default size
+func TestNewWithIODefaultSize(t *testing.T) {
+	r := renderer.NewWithIO(strings.NewReader(""), &bytes.Buffer{}, nil)
+	if r.Width() != 80 || r.Height() != 24 {
+		t.Errorf("size = %dx%d, want 80x24", r.Width(), r.Height())
+	}
+}
+
+// Nested renderers on one output share the alternate screen: it is entered
+// by the first Init and left by the last Restore
+func TestAltScreenNesting(t *testing.T) {
+	var out bytes.Buffer
+	outer := renderer.NewWithIO(strings.NewReader(""), &out, nil).WithAltScreen()
+	inner := renderer.NewWithIO(strings.NewReader(""), &out, nil).WithAltScreen()
+
+	steps := []struct {
+		name string
+		run  func() error
+		want string
+	}{
+		{"outer init", outer.Init, "\033[?1049h"},
+		{"inner init", inner.Init, ""},
+		{"inner restore", inner.Restore, ""},
+		{"outer restore", outer.Restore, "\033[?1049l"},
+	}
+	for _, step := range steps {
+		out.Reset()
+		if err := step.run(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		got := ""
+		for _, seq := range []string{"\033[?1049h", "\033[?1049l"} {
+			if strings.Contains(out.String(), seq) {
+				got += seq
+			}
+		}
+		if got != step.want {
+			t.Errorf("%s switched screens with %q, want %q", step.name, got, step.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package renderer
+
+import (
+	"io"
+
+	"golang.org/x/term"
+)
+
+// Suspend hands every terminal held by a renderer back to the shell and
+// stops the process, as Ctrl+Z does outside raw mode. Once the process is
+// continued the terminals are taken again and each initialized renderer
+// reports a KeyResize event, so that the component on screen re-queries the
+// size and redraws in full. On platforms without job control it does
+// nothing.
+//
+// Renderers call it themselves when Ctrl+Z is read from a terminal or the
+// process receives SIGTSTP.
+func Suspend() {
+	if !canSuspend {
+		return
+	}
+
+	guard.Lock()
+	defer guard.Unlock()
+
+	for r := range guard.active {
+		r.reset()
+	}
+	stopProcess()
+	for r := range guard.active {
+		r.resume()
+	}
+}
+
+// resume takes the terminal again after the process was continued. Like
+// reset it leaves the renderer state alone; the reading goroutine catches up
+// when it receives from r.continued.
+func (r *Renderer) resume() {
+	if r.oldState != nil {
+		term.MakeRaw(int(r.in.(fileDescriptor).Fd()))
+	}
+	if r.inAltScreen {
+		io.WriteString(r.out, "\033[?1049h")
+	}
+	io.WriteString(r.out, r.setupSequence())
+
+	select {
+	case r.continued <- struct{}{}:
+	default:
+	}
+}
+
+// isSuspendKey reports whether event is the Ctrl+Z that the terminal would
+// have turned into SIGTSTP outside raw mode
+func (r *Renderer) isSuspendKey(event *InputEvent) bool {
+	return canSuspend && r.oldState != nil && event != nil &&
+		event.Rune == 'z' && event.Mod == ModCtrl
+}
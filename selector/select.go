@@ -1,12 +1,18 @@
 package selector
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/vynazevedo/termx/help"
+	"github.com/vynazevedo/termx/keymap"
 	"github.com/vynazevedo/termx/renderer"
 	"github.com/vynazevedo/termx/theme"
+	"github.com/vynazevedo/termx/textwidth"
+	"github.com/vynazevedo/termx/widget"
 )
 
 type Select struct {
@@ -18,8 +24,34 @@ type Select struct {
 	renderer     *renderer.Renderer
 	filter       string
 	filtered     []int
+	filtering    bool // editing the filter with a modal keymap
+	inline       bool
+	keymap       *keymap.Keymap
+	theme        *theme.Theme
+
+	// Position of the visible options on screen, used to map mouse clicks
+	listY     int
+	listStart int
+	listEnd   int
+}
+
+// actions are the keymap actions the select responds to
+var actions = []keymap.Action{
+	keymap.Accept,
+	keymap.FilterClear,
+	keymap.FilterStart,
+	keymap.DeleteBackward,
+	keymap.CursorUp,
+	keymap.CursorDown,
+	keymap.CursorHome,
+	keymap.CursorEnd,
+	keymap.PageUp,
+	keymap.PageDown,
 }
 
+// visibleItems is the number of options shown at once
+const visibleItems = 7
+
 func New(label string, options []string, selected *string) *Select {
 	s := &Select{
 		Label:    label,
@@ -67,74 +99,362 @@ func (s *Select) updateFiltered() {
 	}
 }
 
+// WithRenderer draws the select with r instead of a renderer on the standard streams
+func (s *Select) WithRenderer(r *renderer.Renderer) *Select {
+	s.renderer = r
+	return s
+}
+
+// WithKeymap resolves keys through k instead of keymap.Current(). With a
+// modal keymap such as keymap.Vim, typing filters only after filter.start.
+func (s *Select) WithKeymap(k *keymap.Keymap) *Select {
+	s.keymap = k
+	return s
+}
+
+// keys returns the keymap the select resolves keys through
+func (s *Select) keys() *keymap.Keymap {
+	if s.keymap != nil {
+		return s.keymap
+	}
+	return keymap.Current()
+}
+
+// WithTheme draws the select with t instead of theme.Current()
+func (s *Select) WithTheme(t *theme.Theme) *Select {
+	s.theme = t
+	return s
+}
+
+// currentTheme returns the theme the select is drawn with
+func (s *Select) currentTheme() *theme.Theme {
+	if s.theme != nil {
+		return s.theme
+	}
+	return theme.Current()
+}
+
+// Inline renders the select below the previous output at the cursor
+// position instead of taking over the screen. Once answered it collapses
+// to a one-line summary.
+func (s *Select) Inline() *Select {
+	s.inline = true
+	return s
+}
+
 func (s *Select) Run() error {
-	s.renderer = renderer.New()
-	if err := s.renderer.Init(); err != nil {
-		return err
+	return s.RunContext(context.Background())
+}
+
+// RunContext is like Run but stops waiting for the user and returns
+// ctx.Err() once ctx is done
+func (s *Select) RunContext(ctx context.Context) error {
+	if s.renderer == nil {
+		s.renderer = renderer.New()
+		if !s.inline {
+			// Mouse reporting takes over the terminal's own selection and
+			// scrolling, which an inline prompt leaves to the user
+			s.renderer.WithMouse()
+		}
+	}
+
+	var err error
+	if s.inline {
+		err = widget.RunInline(ctx, s.renderer, s)
+	} else {
+		err = widget.Run(ctx, s.renderer, help.WithOverlay(s).WithTheme(s.theme))
+	}
+	if errors.Is(err, renderer.ErrNotTerminal) {
+		return s.runLine(ctx)
+	}
+	if err == nil && s.inline && s.Selected != nil {
+		s.collapse(*s.Selected)
+	}
+	return err
+}
+
+// Focusable reports that the select takes keyboard input
+func (s *Select) Focusable() bool {
+	return true
+}
+
+// HandleEvent moves through and filters the options, and finishes on Enter
+// with the current option stored in Selected
+func (s *Select) HandleEvent(event *renderer.InputEvent) widget.Result {
+	if event.Key == renderer.KeyMouse {
+		if event = s.handleMouse(event.Mouse); event.Key == renderer.KeyUnknown {
+			// The click only moved the cursor, if anything
+			return widget.Handled
+		}
+	}
+
+	if event.Key == renderer.KeyCtrlC {
+		return widget.Done(widget.ErrCancelled)
+	}
+	
+	// A modal keymap leaves plain characters to the bindings until the
+	// filter is being edited
+	textEntry := !s.keys().Modal() || s.filtering
+	
+	switch s.keys().Lookup(event, textEntry, actions...) {
+	case keymap.Accept:
+		if s.filtering {
+			s.filtering = false
+			break
+		}
+		if len(s.filtered) > 0 && s.currentIndex < len(s.filtered) {
+			selectedOption := s.Options[s.filtered[s.currentIndex]]
+			if s.Selected != nil {
+				*s.Selected = selectedOption
+			}
+			return widget.Done(nil)
+		}
+	
+	case keymap.FilterClear:
+		if s.filter == "" && !s.filtering {
+			return widget.Ignored
+		}
+		s.filter = ""
+		s.filtering = false
+		s.updateFiltered()
+	
+	case keymap.FilterStart:
+		if !s.keys().Modal() {
+			return widget.Ignored
+		}
+		s.filtering = true
+	
+	case keymap.DeleteBackward:
+		if len(s.filter) > 0 {
+			filter := []rune(s.filter)
+			s.filter = string(filter[:len(filter)-1])
+			s.updateFiltered()
+		}
+	
+	case keymap.CursorUp:
+		if s.currentIndex > 0 {
+			s.currentIndex--
+		}
+	
+	case keymap.CursorDown:
+		if s.currentIndex < len(s.filtered)-1 {
+			s.currentIndex++
+		}
+	
+	case keymap.CursorHome:
+		s.currentIndex = 0
+	
+	case keymap.CursorEnd:
+		s.currentIndex = max(len(s.filtered)-1, 0)
+	
+	case keymap.PageUp:
+		s.currentIndex = max(s.currentIndex-visibleItems, 0)
+	
+	case keymap.PageDown:
+		s.currentIndex = max(min(s.currentIndex+visibleItems, len(s.filtered)-1), 0)
+	
+	default:
+		switch {
+		case event.Key == renderer.KeyPaste:
+			s.filter += renderer.SingleLine(event.Text)
+		case event.Printable() && textEntry:
+			s.filter += string(event.Rune)
+		default:
+			return widget.Ignored
+		}
+		s.updateFiltered()
+	}
+	return widget.Handled
+}
+
+// KeyBindings lists the keys of the select in its current state, for help
+func (s *Select) KeyBindings() []help.Binding {
+	k := s.keys()
+	bindings := []help.Binding{
+		help.Bind(k, keymap.CursorUp, "up").InBar(),
+		help.Bind(k, keymap.CursorDown, "down").InBar(),
+		help.Bind(k, keymap.Accept, "select").InBar(),
+	}
+	if k.Modal() && !s.filtering {
+		bindings = append(bindings, help.Bind(k, keymap.FilterStart, "filter").InBar())
+	}
+	bindings = append(bindings,
+		help.Bind(k, keymap.FilterClear, "clear filter").InBar(),
+		help.Interrupt.InBar(),
+		help.Bind(k, keymap.CursorHome, "first option"),
+		help.Bind(k, keymap.CursorEnd, "last option"),
+		help.Bind(k, keymap.PageUp, "page up"),
+		help.Bind(k, keymap.PageDown, "page down"),
+		help.Bind(k, keymap.DeleteBackward, "delete from filter"),
+		help.Bind(k, keymap.Help, "help").InBar(),
+	)
+	if s.filtering {
+		bindings[2].Help = "apply filter"
+	}
+	if !k.Modal() || s.filtering {
+		for n := range bindings {
+			bindings[n] = bindings[n].TextEntry()
+		}
+	}
+	return bindings
+}
+
+// handleMouse moves the cursor to the clicked option and translates double
+// clicks and wheel movements into the equivalent key event.
+func (s *Select) handleMouse(m renderer.MouseEvent) *renderer.InputEvent {
+	switch m.Button {
+	case renderer.MouseWheelUp:
+		return &renderer.InputEvent{Key: renderer.KeyArrowUp}
+	case renderer.MouseWheelDown:
+		return &renderer.InputEvent{Key: renderer.KeyArrowDown}
+	case renderer.MouseLeft:
+		idx := s.listStart + m.Y - s.listY
+		if m.Action != renderer.MousePress || m.Y < s.listY || idx >= s.listEnd {
+			break
+		}
+		s.currentIndex = idx
+		if m.Clicks >= 2 {
+			return &renderer.InputEvent{Key: renderer.KeyEnter}
+		}
 	}
-	defer s.renderer.Restore()
+	return &renderer.InputEvent{}
+}
 
+// runLine lists the options with numbers when there is no terminal and
+// reads the choice from a plain line. The answer may be the number or the
+// option itself; an empty answer keeps the current selection.
+func (s *Select) runLine(ctx context.Context) error {
+	th := s.currentTheme()
+	
+	current := ""
+	if s.Selected != nil {
+		current = *s.Selected
+	}
+	
+	s.renderer.Write(th.Primary.Sprint(s.Label) + "\n")
+	for i, opt := range s.Options {
+		s.renderer.Write(fmt.Sprintf("  %d) %s\n", i+1, opt))
+	}
+	
+	prompt := "Choose an option"
+	if current != "" {
+		prompt += " [" + current + "]"
+	}
+	
 	for {
-		s.render()
+		s.renderer.Write(prompt + ": ")
 		
-		event, err := renderer.ReadInput()
+		answer, err := s.renderer.ReadLineContext(ctx)
 		if err != nil {
 			return err
 		}
-
-		switch event.Key {
-		case renderer.KeyCtrlC:
-			return errors.New("cancelled")
+		answer = strings.TrimSpace(answer)
 		
-		case renderer.KeyEnter:
-			if len(s.filtered) > 0 && s.currentIndex < len(s.filtered) {
-				selectedOption := s.Options[s.filtered[s.currentIndex]]
-				if s.Selected != nil {
-					*s.Selected = selectedOption
+		choice := ""
+		if answer == "" {
+			choice = current
+		} else if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(s.Options) {
+			choice = s.Options[n-1]
+		} else {
+			for _, opt := range s.Options {
+				if strings.EqualFold(opt, answer) {
+					choice = opt
+					break
 				}
-				return nil
-			}
-		
-		case renderer.KeyArrowUp:
-			if s.currentIndex > 0 {
-				s.currentIndex--
-			}
-		
-		case renderer.KeyArrowDown:
-			if s.currentIndex < len(s.filtered)-1 {
-				s.currentIndex++
-			}
-		
-		case renderer.KeyBackspace:
-			if len(s.filter) > 0 {
-				s.filter = s.filter[:len(s.filter)-1]
-				s.updateFiltered()
 			}
+		}
 		
-		case renderer.KeyEscape:
-			if s.filter != "" {
-				s.filter = ""
-				s.updateFiltered()
-			}
+		if choice == "" {
+			s.renderer.Write(th.Error.Sprint(fmt.Sprintf("✗ enter a number between 1 and %d", len(s.Options))) + "\n")
+			continue
+		}
 		
-		default:
-			if event.Rune != 0 {
-				s.filter += string(event.Rune)
-				s.updateFiltered()
+		if s.Selected != nil {
+			*s.Selected = choice
+		}
+		return nil
+	}
+}
+
+// visibleRange returns the part of the filtered options that fits in
+// visibleItems rows while keeping the current option in view
+func (s *Select) visibleRange(visibleItems int) (int, int) {
+	startIdx := 0
+	if s.currentIndex >= visibleItems {
+		startIdx = s.currentIndex - visibleItems + 1
+	}
+	
+	endIdx := startIdx + visibleItems
+	if endIdx > len(s.filtered) {
+		endIdx = len(s.filtered)
+		if endIdx-startIdx < visibleItems && startIdx > 0 {
+			startIdx = endIdx - visibleItems
+			if startIdx < 0 {
+				startIdx = 0
 			}
 		}
 	}
+	return startIdx, endIdx
+}
+
+// DrawInline draws the label, filter and visible options as lines below
+// the previous output
+func (s *Select) DrawInline(r *renderer.Renderer) {
+	th := s.currentTheme()
+	
+	prefix := th.Success.Sprint("?") + " " + theme.Bold(s.Label) + ": "
+	header := prefix + s.filter
+	if s.filter == "" && !s.filtering {
+		header = prefix + th.TextDim.Sprint(strings.ToLower(s.filterHint()))
+	}
+	lines := []string{header}
+	
+	startIdx, endIdx := s.visibleRange(visibleItems)
+	// Clicks cannot be mapped to rows of an inline region; the wheel still works
+	// when the renderer given with WithRenderer reports the mouse
+	s.listY, s.listStart, s.listEnd = 0, 0, 0
+	
+	for i := startIdx; i < endIdx; i++ {
+		option := s.Options[s.filtered[i]]
+		if i == s.currentIndex {
+			lines = append(lines, th.Cursor.Sprint("❯ ")+theme.Highlight(option, s.filter, th.Selected, th.Match))
+		} else {
+			lines = append(lines, "  "+theme.Highlight(option, s.filter, theme.Style{}, th.Match))
+		}
+	}
+	if len(s.filtered) == 0 {
+		lines = append(lines, th.TextDim.Sprint("  no matches"))
+	}
+	
+	r.SetCursor(renderer.StringWidth(prefix)+textwidth.String(s.filter), 0)
+	r.DrawInline(lines)
+}
+
+// filterHint tells how to start filtering with the current keymap
+func (s *Select) filterHint() string {
+	if keys := s.keys().Keys(keymap.FilterStart); s.keys().Modal() && len(keys) > 0 {
+		return "Press " + keys[0].String() + " to filter"
+	}
+	return "Type to filter"
 }
 
-func (s *Select) render() {
-	s.renderer.Clear()
-	th := theme.Current()
+// collapse replaces the inline region with a summary of the answer
+func (s *Select) collapse(answer string) {
+	th := s.currentTheme()
+	s.renderer.ClearInline()
+	s.renderer.Write(th.Success.Sprint("?") + " " + theme.Bold(s.Label) + ": " + th.Primary.Sprint(answer) + "\n")
+}
+
+// Draw draws the label and a box with the filter and the visible options
+// centered in area, with the key help on the last rows
+func (s *Select) Draw(c *renderer.Canvas, area renderer.Rect) {
+	th := s.currentTheme()
 	
 	// Calculate dimensions
 	maxOptionLen := 0
 	for _, opt := range s.Options {
-		if len(opt) > maxOptionLen {
-			maxOptionLen = len(opt)
+		if w := textwidth.String(opt); w > maxOptionLen {
+			maxOptionLen = w
 		}
 	}
 	
@@ -142,48 +462,37 @@ func (s *Select) render() {
 	if boxWidth < 40 {
 		boxWidth = 40
 	}
+	boxWidth = min(boxWidth, area.Width)
 	
-	visibleItems := 7
-	boxHeight := visibleItems + 4
+	// Borders, filter, a blank line, the options and the status line
+	boxHeight := visibleItems + 5
 	
-	startX := (s.renderer.Width() - boxWidth) / 2
-	startY := (s.renderer.Height() - boxHeight) / 2
+	startX := max(area.X+(area.Width-boxWidth)/2, area.X)
+	startY := max(area.Y+(area.Height-boxHeight)/2, area.Y+2)
 	
 	// Label
-	s.renderer.PrintCentered(startY-2, th.Primary.Sprint(s.Label))
+	c.PrintCentered(startY-2, th.Primary.Sprint(s.Label))
 	
 	// Main box
-	s.renderer.Box(startX, startY, boxWidth, boxHeight, "")
+	c.StyledBox(renderer.Rect{X: startX, Y: startY, Width: boxWidth, Height: boxHeight}, "", th.Border.Sequence())
 	
 	// Filter display
 	filterY := startY + 1
 	filterX := startX + 2
 	
-	if s.filter != "" {
-		s.renderer.Print(filterX, filterY, th.Info.Sprint("Filter: ") + s.filter)
+	if s.filter != "" || s.filtering {
+		c.Print(filterX, filterY, th.Info.Sprint("Filter: ") + s.filter)
 	} else {
-		s.renderer.Print(filterX, filterY, th.TextDim.Sprint("Type to filter..."))
+		c.Print(filterX, filterY, th.TextDim.Sprint(s.filterHint()+"..."))
 	}
 	
 	// Options
 	optionsY := startY + 3
 	
 	// Calculate visible range
-	startIdx := 0
-	if s.currentIndex >= visibleItems {
-		startIdx = s.currentIndex - visibleItems + 1
-	}
+	startIdx, endIdx := s.visibleRange(visibleItems)
 	
-	endIdx := startIdx + visibleItems
-	if endIdx > len(s.filtered) {
-		endIdx = len(s.filtered)
-		if endIdx-startIdx < visibleItems && startIdx > 0 {
-			startIdx = endIdx - visibleItems
-			if startIdx < 0 {
-				startIdx = 0
-			}
-		}
-	}
+	s.listY, s.listStart, s.listEnd = optionsY, startIdx, endIdx
 	
 	// Render visible options
 	for i := startIdx; i < endIdx; i++ {
@@ -193,10 +502,9 @@ func (s *Select) render() {
 		
 		if i == s.currentIndex {
 			// Selected item
-			s.renderer.Print(startX+1, y, strings.Repeat(" ", boxWidth-2))
-			s.renderer.Print(startX+2, y, th.Selected.Sprint(fmt.Sprintf("▶ %s", option)))
+			c.Print(startX+2, y, th.Selected.Sprint("▶ ")+theme.Highlight(option, s.filter, th.Selected, th.Match))
 		} else {
-			s.renderer.Print(startX+2, y, fmt.Sprintf("  %s", option))
+			c.Print(startX+2, y, "  "+theme.Highlight(option, s.filter, theme.Style{}, th.Match))
 		}
 	}
 	
@@ -209,9 +517,9 @@ func (s *Select) render() {
 		for i := 0; i < scrollHeight; i++ {
 			x := startX + boxWidth - 3
 			if i == scrollPos {
-				s.renderer.Print(x, scrollY+i, th.Primary.Sprint("█"))
+				c.Print(x, scrollY+i, th.Primary.Sprint("█"))
 			} else {
-				s.renderer.Print(x, scrollY+i, th.Border.Sprint("│"))
+				c.Print(x, scrollY+i, th.Border.Sprint("│"))
 			}
 		}
 	}
@@ -219,10 +527,11 @@ func (s *Select) render() {
 	// Status line
 	statusY := startY + boxHeight - 2
 	status := fmt.Sprintf("%d/%d items", len(s.filtered), len(s.Options))
-	s.renderer.Print(startX+2, statusY, th.TextDim.Sprint(status))
+	c.Print(startX+2, statusY, th.TextDim.Sprint(status))
 	
-	// Help text
-	helpY := s.renderer.Height() - 2
-	helpText := "↑↓ Navigate • Enter Select • Type to filter • Esc Clear filter • Ctrl+C Cancel"
-	s.renderer.PrintCentered(helpY, th.TextDim.Sprint(helpText))
-}
\ No newline at end of file
+	// Help text, when there is room left for it
+	helpY := area.Y + area.Height - 2
+	if helpY >= startY+boxHeight {
+		c.PrintCentered(helpY, help.Bar(s.KeyBindings(), area.Width, th))
+	}
+}
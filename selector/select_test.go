@@ -0,0 +1,110 @@
+package selector_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/vynazevedo/termx/renderer"
+	"github.com/vynazevedo/termx/selector"
+	"github.com/vynazevedo/termx/termxtest"
+)
+
+var fruits = []string{"apple", "banana", "blueberry", "cherry", "grape", "mango", "orange", "pear", "plum", "strawberry"}
+
+// The script runs out after the keys, so Run returns io.EOF with the last
+// frame on the screen
+func TestRenderGolden(t *testing.T) {
+	tests := []struct {
+		name string
+		keys string
+	}{
+		{"initial", ""},
+		{"filtered", "an"},
+		{"scrolled", strings.Repeat(termxtest.KeyDown, 9)},
+		{"no_matches", "kiwi"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term := termxtest.NewTerminal(50, 16)
+			if tt.keys != "" {
+				term.Type(tt.keys)
+			}
+
+			var choice string
+			if err := selector.New("Fruit", fruits, &choice).WithRenderer(term.Renderer()).Run(); err != io.EOF {
+				t.Fatalf("Run() error = %v, want %v", err, io.EOF)
+			}
+			term.AssertGolden(t, "select_"+tt.name)
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name  string
+		input func(*termxtest.Terminal)
+		want  string
+	}{
+		{"enter", func(t *termxtest.Terminal) { t.Press(termxtest.KeyEnter) }, "apple"},
+		{"arrows", func(t *termxtest.Terminal) {
+			t.Press(termxtest.KeyDown, termxtest.KeyDown, termxtest.KeyUp, termxtest.KeyEnter)
+		}, "banana"},
+		{"filter", func(t *termxtest.Terminal) { t.Type("berr").Press(termxtest.KeyDown, termxtest.KeyEnter) }, "strawberry"},
+		{"paste", func(t *termxtest.Terminal) { t.Paste("plum\n").Press(termxtest.KeyEnter) }, "plum"},
+		{"double click", func(t *termxtest.Terminal) { t.Click(20, 6).Click(20, 6) }, "banana"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term := termxtest.NewTerminal(50, 16)
+			tt.input(term)
+
+			var choice string
+			err := selector.New("Fruit", fruits, &choice).WithRenderer(term.Renderer()).Run()
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if choice != tt.want {
+				t.Errorf("Run() chose %q, want %q", choice, tt.want)
+			}
+		})
+	}
+}
+
+// Without a terminal the options are numbered and the answer is read from
+// a line of the input
+func TestRunLine(t *testing.T) {
+	tests := []struct {
+		input   string
+		current string
+		want    string
+		err     error
+	}{
+		{"2\n", "", "banana", nil},
+		{"Cherry\r\n", "", "cherry", nil},
+		{"\n", "plum", "plum", nil},
+		{"11\nkiwi\n3\n", "", "blueberry", nil},
+		{"", "", "", io.EOF},
+	}
+	for _, tt := range tests {
+		in, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, tt.input)
+		w.Close()
+
+		var out bytes.Buffer
+		choice := tt.current
+		err = selector.New("Fruit", fruits, &choice).WithRenderer(renderer.NewWithIO(in, &out, nil)).Run()
+		in.Close()
+		if err != tt.err || choice != tt.want {
+			t.Errorf("input %q: chose %q with error %v, want %q with %v", tt.input, choice, err, tt.want, tt.err)
+		}
+		if !strings.Contains(out.String(), "  10) strawberry") {
+			t.Errorf("input %q: options were not listed:\n%s", tt.input, out.String())
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/vynazevedo/termx/renderer"
 	"github.com/vynazevedo/termx/theme"
+	"github.com/vynazevedo/termx/textwidth"
 )
 
 // SpinnerStyle defines different spinner animations
@@ -54,11 +55,9 @@ func New() *Spinner {
 	return &Spinner{
 		style:    Dots,
 		label:    "Carregando...",
-		color:    "\033[36m", // cyan
 		speed:    100 * time.Millisecond,
 		done:     make(chan bool),
 		renderer: renderer.New(),
-		theme:    theme.Default,
 	}
 }
 
@@ -78,7 +77,8 @@ func (s *Spinner) WithLabel(label string) *Spinner {
 	return s
 }
 
-// WithColor sets the spinner color
+// WithColor sets the spinner color, an SGR sequence, instead of the
+// primary color of the theme
 func (s *Spinner) WithColor(color string) *Spinner {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -94,12 +94,35 @@ func (s *Spinner) WithSpeed(speed time.Duration) *Spinner {
 	return s
 }
 
-// WithTheme sets the spinner theme
+// WithTheme draws the spinner with t instead of theme.Current()
 func (s *Spinner) WithTheme(t *theme.Theme) *Spinner {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.theme = t
-	s.color = t.Primary.Foreground
+	return s
+}
+
+// currentTheme returns the theme the spinner is drawn with
+func (s *Spinner) currentTheme() *theme.Theme {
+	if s.theme != nil {
+		return s.theme
+	}
+	return theme.Current()
+}
+
+// frameColor returns the SGR sequence frames are drawn in
+func (s *Spinner) frameColor() string {
+	if s.color != "" {
+		return s.color
+	}
+	return s.currentTheme().Primary.Sequence()
+}
+
+// WithRenderer sets the renderer used to draw the spinner
+func (s *Spinner) WithRenderer(r *renderer.Renderer) *Spinner {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.renderer = r
 	return s
 }
 
@@ -129,19 +152,19 @@ func (s *Spinner) Stop() {
 	s.done <- true
 	
 	// Clear the spinner line
-	fmt.Print("\r" + strings.Repeat(" ", len(s.label)+10) + "\r")
+	s.renderer.Write("\r" + strings.Repeat(" ", textwidth.String(s.label)+10) + "\r")
 }
 
 // StopWithMessage stops the spinner and shows a completion message
 func (s *Spinner) StopWithMessage(message string) {
 	s.Stop()
-	fmt.Printf("✓ %s%s\n", s.theme.Success.Sprint(message), theme.Reset())
+	s.renderer.Printf("✓ %s%s\n", s.currentTheme().Success.Sprint(message), theme.Reset())
 }
 
 // StopWithError stops the spinner and shows an error message
 func (s *Spinner) StopWithError(message string) {
 	s.Stop()
-	fmt.Printf("✗ %s%s\n", s.theme.Error.Sprint(message), theme.Reset())
+	s.renderer.Printf("✗ %s%s\n", s.currentTheme().Error.Sprint(message), theme.Reset())
 }
 
 // IsActive returns whether the spinner is currently running
@@ -170,8 +193,8 @@ func (s *Spinner) animate() {
 			}
 			
 			frame := frames[frameIndex%len(frames)]
-			output := fmt.Sprintf("\r%s%s%s %s", s.color, frame, theme.Reset(), s.label)
-			fmt.Print(output)
+			output := fmt.Sprintf("\r%s%s%s %s", s.frameColor(), frame, theme.Reset(), s.label)
+			s.renderer.Write(output)
 			
 			frameIndex++
 			s.mu.RUnlock()
@@ -186,8 +209,8 @@ func (s *Spinner) Render() {
 	
 	frames := spinnerFrames[s.style]
 	frame := frames[0] // Use first frame for static display
-	output := fmt.Sprintf("%s%s%s %s", s.color, frame, theme.Reset(), s.label)
-	fmt.Print(output)
+	output := fmt.Sprintf("%s%s%s %s", s.frameColor(), frame, theme.Reset(), s.label)
+	s.renderer.Write(output)
 }
 
 // Close cleans up the spinner resources
@@ -1,10 +1,13 @@
 package table
 
 import (
-	"fmt"
+	"context"
 	"strings"
+	"github.com/vynazevedo/termx/help"
+	"github.com/vynazevedo/termx/keymap"
 	"github.com/vynazevedo/termx/renderer"
 	"github.com/vynazevedo/termx/theme"
+	"github.com/vynazevedo/termx/widget"
 )
 
 type Table struct {
@@ -15,6 +18,23 @@ type Table struct {
 	compact bool
 	selectedRow int
 	interactive bool
+	renderer    *renderer.Renderer
+	rowsY       int
+	offset      int // first row on screen
+	shown       int // number of rows on screen
+	chosen      int
+	keymap      *keymap.Keymap
+	theme       *theme.Theme
+}
+
+// actions are the keymap actions an interactive table responds to
+var actions = []keymap.Action{
+	keymap.CursorUp,
+	keymap.CursorDown,
+	keymap.CursorHome,
+	keymap.CursorEnd,
+	keymap.Accept,
+	keymap.Cancel,
 }
 
 func New(headers []string) *Table {
@@ -31,8 +51,8 @@ func (t *Table) AddRow(row ...string) *Table {
 	if len(row) == len(t.headers) {
 		t.rows = append(t.rows, row)
 		for i, cell := range row {
-			if len(cell) > t.widths[i] {
-				t.widths[i] = len(cell)
+			if w := renderer.StringWidth(cell); w > t.widths[i] {
+				t.widths[i] = w
 			}
 		}
 	}
@@ -55,111 +75,285 @@ func (t *Table) Interactive() *Table {
 	return t
 }
 
+// WithRenderer draws the table with r instead of a renderer on the standard streams
+func (t *Table) WithRenderer(r *renderer.Renderer) *Table {
+	t.renderer = r
+	return t
+}
+
+// WithKeymap resolves keys through k instead of keymap.Current()
+func (t *Table) WithKeymap(k *keymap.Keymap) *Table {
+	t.keymap = k
+	return t
+}
+
+// keys returns the keymap the table resolves keys through
+func (t *Table) keys() *keymap.Keymap {
+	if t.keymap != nil {
+		return t.keymap
+	}
+	return keymap.Current()
+}
+
+// WithTheme draws the table with t instead of theme.Current()
+func (t *Table) WithTheme(th *theme.Theme) *Table {
+	t.theme = th
+	return t
+}
+
+// currentTheme returns the theme the table is drawn with
+func (t *Table) currentTheme() *theme.Theme {
+	if t.theme != nil {
+		return t.theme
+	}
+	return theme.Current()
+}
+
+func (t *Table) newRenderer() *renderer.Renderer {
+	if t.renderer != nil {
+		return t.renderer
+	}
+	return renderer.New()
+}
+
 func (t *Table) Run() (int, error) {
+	return t.RunContext(context.Background())
+}
+
+// RunContext is like Run but stops waiting for the user and returns
+// ctx.Err() once ctx is done
+func (t *Table) RunContext(ctx context.Context) (int, error) {
 	if !t.interactive {
 		t.Render()
 		return -1, nil
 	}
 	
-	r := renderer.New()
-	defer r.Close()
+	r := t.newRenderer()
+	if t.renderer == nil {
+		r.WithMouse().WithAltScreen()
+	}
+	if err := widget.Run(ctx, r, help.WithOverlay(t).WithTheme(t.theme)); err != nil {
+		return -1, err
+	}
+	return t.chosen, nil
+}
+
+// Focusable reports whether the table takes keyboard input, which only an
+// interactive table does
+func (t *Table) Focusable() bool {
+	return t.interactive
+}
+
+// HandleEvent moves the selection and finishes on Enter with the selected
+// row, or on Esc with none
+func (t *Table) HandleEvent(event *renderer.InputEvent) widget.Result {
+	if !t.interactive {
+		return widget.Ignored
+	}
+	if event.Key == renderer.KeyMouse {
+		if event = t.handleMouse(event.Mouse); event.Key == renderer.KeyUnknown {
+			return widget.Handled
+		}
+	}
 	
-	for {
-		t.render(r)
-		
-		key, err := r.ReadKey()
-		if err != nil {
-			return -1, err
+	switch t.keys().Lookup(event, false, actions...) {
+	case keymap.CursorUp:
+		if t.selectedRow > 0 {
+			t.selectedRow--
 		}
-		
-		switch key {
-		case renderer.KeyArrowUp:
-			if t.selectedRow > 0 {
-				t.selectedRow--
-			}
-		case renderer.KeyArrowDown:
-			if t.selectedRow < len(t.rows)-1 {
-				t.selectedRow++
-			}
-		case renderer.KeyEnter:
-			return t.selectedRow, nil
-		case renderer.KeyEscape:
-			return -1, nil
+	case keymap.CursorDown:
+		if t.selectedRow < len(t.rows)-1 {
+			t.selectedRow++
+		}
+	case keymap.CursorHome:
+		t.selectedRow = 0
+	case keymap.CursorEnd:
+		t.selectedRow = max(len(t.rows)-1, 0)
+	case keymap.Accept:
+		t.chosen = t.selectedRow
+		return widget.Done(nil)
+	case keymap.Cancel:
+		t.chosen = -1
+		return widget.Done(nil)
+	default:
+		return widget.Ignored
+	}
+	return widget.Handled
+}
+
+// KeyBindings lists the keys of an interactive table, for help
+func (t *Table) KeyBindings() []help.Binding {
+	if !t.interactive {
+		return nil
+	}
+	k := t.keys()
+	return []help.Binding{
+		help.Bind(k, keymap.CursorUp, "up").InBar(),
+		help.Bind(k, keymap.CursorDown, "down").InBar(),
+		help.Bind(k, keymap.CursorHome, "first row"),
+		help.Bind(k, keymap.CursorEnd, "last row"),
+		help.Bind(k, keymap.Accept, "choose row").InBar(),
+		help.Bind(k, keymap.Cancel, "close").InBar(),
+		help.Interrupt,
+		help.Bind(k, keymap.Help, "help").InBar(),
+	}
+}
+
+// handleMouse selects the clicked row and translates double clicks and
+// wheel movements into the equivalent key event.
+func (t *Table) handleMouse(m renderer.MouseEvent) *renderer.InputEvent {
+	switch m.Button {
+	case renderer.MouseWheelUp:
+		return &renderer.InputEvent{Key: renderer.KeyArrowUp}
+	case renderer.MouseWheelDown:
+		return &renderer.InputEvent{Key: renderer.KeyArrowDown}
+	case renderer.MouseLeft:
+		row := m.Y - t.rowsY
+		if m.Action != renderer.MousePress || row < 0 || row >= t.shown {
+			break
+		}
+		t.selectedRow = t.offset + row
+		if m.Clicks >= 2 {
+			return &renderer.InputEvent{Key: renderer.KeyEnter}
 		}
-		
-		r.ClearScreen()
 	}
+	return &renderer.InputEvent{}
 }
 
 func (t *Table) Render() {
-	r := renderer.New()
+	r := t.newRenderer()
 	defer r.Close()
-	t.render(r)
+	for _, line := range t.lines(0, len(t.rows)) {
+		r.Write(line)
+		r.NewLine()
+	}
 }
 
-func (t *Table) render(r *renderer.Renderer) {
-	th := theme.Current()
+// Draw draws the table from the top left corner of area. An interactive
+// table shows the rows that fit in area, scrolled to keep the selected row
+// in view, and the help bar below them.
+func (t *Table) Draw(c *renderer.Canvas, area renderer.Rect) {
+	start, end := 0, len(t.rows)
+	if t.interactive {
+		visible := t.visibleRows(area.Height)
+		t.scrollTo(visible)
+		start, end = t.offset, min(t.offset+visible, len(t.rows))
+	}
+	lines := t.lines(start, end)
+	for i, line := range lines {
+		c.Print(area.X, area.Y+i, line)
+	}
+	t.rowsY = area.Y + t.headerHeight()
+	t.shown = end - start
+	
+	if t.interactive {
+		c.Print(area.X, area.Y+len(lines)+1, help.Bar(t.KeyBindings(), area.Width, t.currentTheme()))
+	}
+}
+
+// visibleRows returns how many rows fit in height lines together with the
+// header, the borders and the help bar, at least one
+func (t *Table) visibleRows(height int) int {
+	n := height - t.headerHeight() - 2
+	if t.border {
+		n--
+	}
+	return max(n, 1)
+}
+
+// scrollTo moves the scroll offset as little as needed to show the
+// selected row among visible rows
+func (t *Table) scrollTo(visible int) {
+	if t.selectedRow < t.offset {
+		t.offset = t.selectedRow
+	}
+	if t.selectedRow >= t.offset+visible {
+		t.offset = t.selectedRow - visible + 1
+	}
+	t.offset = max(min(t.offset, len(t.rows)-visible), 0)
+}
+
+// headerHeight returns the number of lines above the first row
+func (t *Table) headerHeight() int {
+	switch {
+	case t.border:
+		return 3
+	case !t.compact:
+		return 2
+	}
+	return 1
+}
+
+// lines returns the table with the rows from start to end as styled text,
+// one line per row of output
+func (t *Table) lines(start, end int) []string {
+	th := t.currentTheme()
+	bar := th.Border.Sprint("│")
 	
 	for i, h := range t.headers {
-		if len(h) > t.widths[i] {
-			t.widths[i] = len(h)
+		if w := renderer.StringWidth(h); w > t.widths[i] {
+			t.widths[i] = w
 		}
 	}
 	
+	var lines []string
 	if t.border {
-		t.renderBorder(r, "top")
+		lines = append(lines, th.Border.Sprint(t.borderLine("top")))
 	}
 	
+	var line strings.Builder
 	for i, h := range t.headers {
 		if i == 0 && t.border {
-			r.Write("│ ")
+			line.WriteString(bar + " ")
 		}
-		r.WriteStyled(fmt.Sprintf("%-*s", t.widths[i], h), th.Primary.Foreground)
+		line.WriteString(th.Primary.Sprint(renderer.PadRight(h, t.widths[i])))
 		if i < len(t.headers)-1 {
-			r.Write(" │ ")
+			line.WriteString(" " + bar + " ")
 		} else if t.border {
-			r.Write(" │")
+			line.WriteString(" " + bar)
 		}
 	}
-	r.NewLine()
+	lines = append(lines, line.String())
 	
 	if t.border {
-		t.renderBorder(r, "middle")
+		lines = append(lines, th.Border.Sprint(t.borderLine("middle")))
 	} else if !t.compact {
-		r.WriteStyled(strings.Repeat("─", t.totalWidth()), th.Muted.Foreground)
-		r.NewLine()
+		lines = append(lines, th.Border.Sprint(strings.Repeat("─", t.totalWidth())))
 	}
 	
-	for idx, row := range t.rows {
+	for idx := start; idx < end; idx++ {
+		row := t.rows[idx]
 		isSelected := t.interactive && idx == t.selectedRow
 		
+		line.Reset()
 		for i, cell := range row {
 			if i == 0 && t.border {
-				r.Write("│ ")
+				line.WriteString(bar + " ")
 			}
 			
-			cellText := fmt.Sprintf("%-*s", t.widths[i], cell)
+			cellText := renderer.PadRight(cell, t.widths[i])
 			if isSelected {
-				r.WriteStyled(cellText, th.Success.Foreground)
+				line.WriteString(th.Selected.Sprint(cellText))
 			} else {
-				r.Write(cellText)
+				line.WriteString(cellText)
 			}
 			
 			if i < len(row)-1 {
-				r.Write(" │ ")
+				line.WriteString(" " + bar + " ")
 			} else if t.border {
-				r.Write(" │")
+				line.WriteString(" " + bar)
 			}
 		}
-		r.NewLine()
+		lines = append(lines, line.String())
 	}
 	
 	if t.border {
-		t.renderBorder(r, "bottom")
+		lines = append(lines, th.Border.Sprint(t.borderLine("bottom")))
 	}
+	return lines
 }
 
-func (t *Table) renderBorder(r *renderer.Renderer, position string) {
+func (t *Table) borderLine(position string) string {
 	chars := map[string][]string{
 		"top":    {"┌", "┬", "┐", "─"},
 		"middle": {"├", "┼", "┤", "─"},
@@ -167,15 +361,16 @@ func (t *Table) renderBorder(r *renderer.Renderer, position string) {
 	}
 	
 	c := chars[position]
-	r.Write(c[0])
+	var line strings.Builder
+	line.WriteString(c[0])
 	for i, w := range t.widths {
-		r.Write(strings.Repeat(c[3], w+2))
+		line.WriteString(strings.Repeat(c[3], w+2))
 		if i < len(t.widths)-1 {
-			r.Write(c[1])
+			line.WriteString(c[1])
 		}
 	}
-	r.Write(c[2])
-	r.NewLine()
+	line.WriteString(c[2])
+	return line.String()
 }
 
 func (t *Table) totalWidth() int {
@@ -184,4 +379,4 @@ func (t *Table) totalWidth() int {
 		total += w + 3
 	}
 	return total - 1
-}
\ No newline at end of file
+}
@@ -0,0 +1,61 @@
+package table_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/vynazevedo/termx/table"
+	"github.com/vynazevedo/termx/termxtest"
+	"github.com/vynazevedo/termx/theme"
+)
+
+// newTable returns an interactive table with n rows named row-00, row-01...
+func newTable(n int) *table.Table {
+	t := table.New([]string{"Name", "Index"}).Interactive()
+	for i := range n {
+		t.AddRow(fmt.Sprintf("row-%02d", i), fmt.Sprint(i))
+	}
+	return t
+}
+
+// Rows below the screen scroll into view as the selection reaches them,
+// and clicks select the row drawn under the pointer
+func TestInteractiveScroll(t *testing.T) {
+	term := termxtest.NewTerminal(40, 10)
+	for range 15 {
+		term.Press(termxtest.KeyDown)
+	}
+	// Border, header and separator take 3 lines and the bottom border and
+	// help bar 3 more, leaving 4 rows: row-12 to row-15, from line 3
+	term.Click(2, 3).Press(termxtest.KeyEnter)
+
+	chosen, err := newTable(30).WithRenderer(term.Renderer()).Run()
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if chosen != 12 {
+		t.Errorf("Run() = %d, want 12", chosen)
+	}
+	term.AssertContains(t, "row-15")
+	term.AssertNotContains(t, "row-11")
+	term.AssertContains(t, "choose row")
+}
+
+func TestRenderGolden(t *testing.T) {
+	tests := []struct {
+		name  string
+		table *table.Table
+	}{
+		{"border", table.New([]string{"Pod", "Status"})},
+		{"no_border", table.New([]string{"Pod", "Status"}).WithBorder(false)},
+		{"compact", table.New([]string{"Pod", "Status"}).WithBorder(false).Compact()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term := termxtest.NewTerminal(40, 8)
+			tt.table.AddRow("api-7f9c", "Running").AddRow("worker-日本", "Pending")
+			tt.table.WithTheme(theme.Default).WithRenderer(term.Renderer()).Render()
+			term.AssertGolden(t, "table_"+tt.name)
+		})
+	}
+}
@@ -34,6 +34,9 @@ var (
 	// Layout components
 	Split     = layout.NewSplit
 	BoxLayout = layout.NewBox
+	Group     = layout.NewGroup
+	Pane      = layout.NewPane
+	Focus     = layout.NewFocusManager
 	
 	// Validators
 	Required  = input.Required
@@ -61,6 +64,19 @@ func GetTheme() *theme.Theme {
 	return theme.Current()
 }
 
+// LoadTheme reads a theme from a JSON, YAML or TOML file
+func LoadTheme(path string) (*theme.Theme, error) {
+	return theme.Load(path)
+}
+
+// DetectBackground asks the terminal whether its background is light or
+// dark, so that themes pick the matching colors. Components on the standard
+// streams ask once when they first start; call it to know the answer before
+// that, such as for styled output printed before any component runs.
+func DetectBackground() bool {
+	return theme.DetectBackground()
+}
+
 func ClearScreen() {
 	renderer.ClearScreen()
 }
\ No newline at end of file
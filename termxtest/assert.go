@@ -0,0 +1,80 @@
+package termxtest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// UpdateGoldenEnv is the environment variable that makes the golden helpers
+// rewrite their files instead of comparing against them, as in
+//
+//	TERMX_UPDATE_GOLDEN=1 go test ./...
+const UpdateGoldenEnv = "TERMX_UPDATE_GOLDEN"
+
+// AssertContains fails the test when text does not appear on the screen
+func (s *Screen) AssertContains(tb testing.TB, text string) {
+	tb.Helper()
+	if !s.Contains(text) {
+		tb.Errorf("screen does not contain %q:\n%s", text, frame(s.Text()))
+	}
+}
+
+// AssertNotContains fails the test when text appears on the screen
+func (s *Screen) AssertNotContains(tb testing.TB, text string) {
+	tb.Helper()
+	if s.Contains(text) {
+		tb.Errorf("screen unexpectedly contains %q:\n%s", text, frame(s.Text()))
+	}
+}
+
+// AssertLine fails the test when row y, without trailing spaces, is not want
+func (s *Screen) AssertLine(tb testing.TB, y int, want string) {
+	tb.Helper()
+	if got := s.Line(y); got != want {
+		tb.Errorf("line %d = %q, want %q\n%s", y, got, want, frame(s.Text()))
+	}
+}
+
+// AssertGolden compares the screen text with testdata/<name>.golden
+func (s *Screen) AssertGolden(tb testing.TB, name string) {
+	tb.Helper()
+	AssertGolden(tb, name, s.Text())
+}
+
+// AssertGolden compares got with the content of testdata/<name>.golden,
+// relative to the package under test. When TERMX_UPDATE_GOLDEN is set the
+// file is written with got instead.
+func AssertGolden(tb testing.TB, name, got string) {
+	tb.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			tb.Fatalf("creating golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			tb.Fatalf("writing golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		tb.Fatalf("reading golden file (run with %s=1 to create it): %v", UpdateGoldenEnv, err)
+	}
+	if string(want) != got {
+		tb.Errorf("%s does not match (run with %s=1 to update)\ngot:\n%s\nwant:\n%s", path, UpdateGoldenEnv, frame(got), frame(string(want)))
+	}
+}
+
+// frame draws a border around screen text so trailing blanks are visible
+func frame(text string) string {
+	lines := strings.Split(text, "\n")
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString("│" + line + "\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,594 @@
+// Package termxtest runs termx components against an emulated terminal so
+// that their behavior can be checked in tests without a real TTY.
+//
+// A Terminal provides a renderer whose output is interpreted by a Screen, a
+// grid of cells that understands cursor movement, erasing and SGR styling,
+// and whose input is a script of keystrokes:
+//
+//	term := termxtest.NewTerminal(80, 24)
+//	term.Type("gopher").Press(termxtest.KeyEnter)
+//
+//	var name string
+//	err := input.New("Name", &name).WithRenderer(term.Renderer()).Run()
+//
+//	term.AssertContains(t, "Name")
+//	if name != "gopher" { ... }
+//
+// When the script runs out, the renderer reports io.EOF, so a component
+// waiting for more keys returns an error instead of blocking the test.
+package termxtest
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/vynazevedo/termx/textwidth"
+)
+
+// Style is the rendition of a cell. Colors hold the SGR parameters that
+// selected them, such as "36", "38;5;202" or "48;2;0;0;0", and are empty
+// for the default color.
+type Style struct {
+	Fg        string
+	Bg        string
+	Bold      bool
+	Dim       bool
+	Italic    bool
+	Underline bool
+	Blink     bool
+	Reverse   bool
+	Strike    bool
+}
+
+// Cell is a character position of the screen. The second cell of a wide
+// character has Width 0 and no text.
+type Cell struct {
+	Text  string
+	Width int
+	Style Style
+}
+
+var blankCell = Cell{Text: " ", Width: 1}
+
+// Screen emulates the display of a VT100 style terminal. It implements
+// io.Writer; everything written to it is interpreted as terminal output.
+// Line feeds also return to the first column, as on a terminal with output
+// post-processing enabled.
+type Screen struct {
+	mu sync.Mutex
+
+	width  int
+	height int
+	cells  []Cell
+	x, y   int
+	style  Style
+
+	savedX, savedY int
+	main           []Cell // primary screen while the alternate screen is shown
+	cursorHidden   bool
+	modes          map[int]bool
+	pending        []byte
+	writes         int
+	written        *sync.Cond
+}
+
+// NewScreen creates a blank screen of width columns and height rows
+func NewScreen(width, height int) *Screen {
+	s := &Screen{modes: make(map[int]bool)}
+	s.written = sync.NewCond(&s.mu)
+	s.resize(width, height)
+	return s
+}
+
+// Resize changes the size of the screen, keeping the content that still fits
+func (s *Screen) Resize(width, height int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resize(width, height)
+}
+
+func (s *Screen) resize(width, height int) {
+	s.cells = s.resized(s.cells, width, height)
+	if s.main != nil {
+		s.main = s.resized(s.main, width, height)
+	}
+	s.width, s.height = width, height
+	s.x = clamp(s.x, 0, width-1)
+	s.y = clamp(s.y, 0, height-1)
+}
+
+// resized copies a grid of the current size into a grid of width x height
+func (s *Screen) resized(old []Cell, width, height int) []Cell {
+	cells := make([]Cell, width*height)
+	for i := range cells {
+		cells[i] = blankCell
+	}
+	for y := 0; y < height && y < s.height; y++ {
+		for x := 0; x < width && x < s.width; x++ {
+			cells[y*width+x] = old[y*s.width+x]
+		}
+	}
+	return cells
+}
+
+// Size returns the number of columns and rows of the screen
+func (s *Screen) Size() (width, height int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.width, s.height
+}
+
+// Cursor returns the position of the cursor
+func (s *Screen) Cursor() (x, y int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.x, s.y
+}
+
+// CursorVisible reports whether the cursor is shown
+func (s *Screen) CursorVisible() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.cursorHidden
+}
+
+// Mode reports whether the private mode n (as in ESC [ ? n h) is set
+func (s *Screen) Mode(n int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.modes[n]
+}
+
+// AltScreen reports whether the alternate screen is shown
+func (s *Screen) AltScreen() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.main != nil
+}
+
+// Cell returns the cell at x, y
+func (s *Screen) Cell(x, y int) Cell {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if x < 0 || y < 0 || x >= s.width || y >= s.height {
+		return blankCell
+	}
+	return s.cells[y*s.width+x]
+}
+
+// Line returns the text of row y without trailing spaces
+func (s *Screen) Line(y int) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.line(y)
+}
+
+func (s *Screen) line(y int) string {
+	if y < 0 || y >= s.height {
+		return ""
+	}
+	var b strings.Builder
+	for _, c := range s.cells[y*s.width : (y+1)*s.width] {
+		b.WriteString(c.Text)
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// Lines returns the text of every row without trailing spaces
+func (s *Screen) Lines() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lines := make([]string, s.height)
+	for y := range lines {
+		lines[y] = s.line(y)
+	}
+	return lines
+}
+
+// Text returns the screen content as lines joined by "\n", without
+// trailing spaces and trailing blank lines
+func (s *Screen) Text() string {
+	return strings.TrimRight(strings.Join(s.Lines(), "\n"), "\n")
+}
+
+// Contains reports whether text appears on a single row of the screen
+func (s *Screen) Contains(text string) bool {
+	_, _, ok := s.Find(text)
+	return ok
+}
+
+// Find returns the position of the first occurrence of text on the screen
+func (s *Screen) Find(text string) (x, y int, ok bool) {
+	for y, line := range s.Lines() {
+		if i := strings.Index(line, text); i >= 0 {
+			return textwidth.String(line[:i]), y, true
+		}
+	}
+	return 0, 0, false
+}
+
+// Write interprets p as terminal output. Sequences split between writes
+// are completed by the following write.
+func (s *Screen) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data := append(s.pending, p...)
+	s.pending = nil
+
+	for i := 0; i < len(data); {
+		n := s.interpret(data[i:])
+		if n == 0 {
+			s.pending = append([]byte(nil), data[i:]...)
+			break
+		}
+		i += n
+	}
+
+	s.writes++
+	s.written.Broadcast()
+	return len(p), nil
+}
+
+// interpret handles the control, sequence or text at the start of data and
+// returns how many bytes it used, or 0 when data ends in an incomplete sequence
+func (s *Screen) interpret(data []byte) int {
+	switch b := data[0]; {
+	case b == '\033':
+		return s.escape(data)
+	case b == '\r':
+		s.x = 0
+	case b == '\n':
+		s.x = 0
+		s.lineFeed()
+	case b == '\b':
+		if s.x > 0 {
+			s.x--
+		}
+	case b == '\t':
+		s.x = min((s.x/8+1)*8, s.width-1)
+	case b < 32 || b == 0x7f:
+		// Other control characters have no visible effect
+	default:
+		// A grapheme cluster is never longer than this in practice
+		end := 1
+		for end < len(data) && end < 64 && data[end] >= 32 && data[end] != 0x7f {
+			end++
+		}
+		if !utf8.FullRune(data[:end]) {
+			return 0
+		}
+		cluster, width := textwidth.FirstCluster(string(data[:end]))
+		s.put(cluster, width)
+		return len(cluster)
+	}
+	return 1
+}
+
+func (s *Screen) put(cluster string, width int) {
+	if width == 0 {
+		if s.x > 0 {
+			prev := &s.cells[s.y*s.width+s.x-1]
+			prev.Text += cluster
+		}
+		return
+	}
+	if s.x+width > s.width {
+		s.x = 0
+		s.lineFeed()
+	}
+	s.set(s.x, s.y, Cell{Text: cluster, Width: width, Style: s.style})
+	if width == 2 && s.x+1 < s.width {
+		s.set(s.x+1, s.y, Cell{Width: 0, Style: s.style})
+	}
+	s.x += width
+	if s.x >= s.width {
+		// Stay on the last column until the next character wraps
+		s.x = s.width
+	}
+}
+
+// set replaces a cell, blanking the other half of a wide character it breaks
+func (s *Screen) set(x, y int, c Cell) {
+	i := y*s.width + x
+	switch s.cells[i].Width {
+	case 0:
+		if x > 0 {
+			s.cells[i-1] = blankCell
+		}
+	case 2:
+		if x+1 < s.width {
+			s.cells[i+1] = blankCell
+		}
+	}
+	s.cells[i] = c
+}
+
+func (s *Screen) lineFeed() {
+	if s.y < s.height-1 {
+		s.y++
+		return
+	}
+	s.scrollUp(0, 1)
+}
+
+// scrollUp removes n rows starting at top, moving the rows below up
+func (s *Screen) scrollUp(top, n int) {
+	for y := top; y < s.height; y++ {
+		for x := 0; x < s.width; x++ {
+			if y+n < s.height {
+				s.cells[y*s.width+x] = s.cells[(y+n)*s.width+x]
+			} else {
+				s.cells[y*s.width+x] = blankCell
+			}
+		}
+	}
+}
+
+// scrollDown inserts n blank rows at top, moving the rows below down
+func (s *Screen) scrollDown(top, n int) {
+	for y := s.height - 1; y >= top; y-- {
+		for x := 0; x < s.width; x++ {
+			if y-n >= top {
+				s.cells[y*s.width+x] = s.cells[(y-n)*s.width+x]
+			} else {
+				s.cells[y*s.width+x] = blankCell
+			}
+		}
+	}
+}
+
+func (s *Screen) erase(from, to int) {
+	from = clamp(from, 0, len(s.cells))
+	to = clamp(to, 0, len(s.cells))
+	for i := from; i < to; i++ {
+		s.cells[i] = blankCell
+	}
+}
+
+// escape handles an escape sequence and returns its length, or 0 when it is incomplete
+func (s *Screen) escape(data []byte) int {
+	if len(data) < 2 {
+		return 0
+	}
+
+	switch data[1] {
+	case '[':
+		i := 2
+		for i < len(data) && data[i] >= 0x20 && data[i] <= 0x3f {
+			i++
+		}
+		if i == len(data) {
+			return 0
+		}
+		s.csi(string(data[2:i]), data[i])
+		return i + 1
+	case ']', 'P', 'X', '^', '_':
+		// String sequences are consumed without effect
+		for i := 2; i < len(data); i++ {
+			if data[i] == '\a' && data[1] == ']' {
+				return i + 1
+			}
+			if data[i] == '\033' && i+1 < len(data) && data[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return 0
+	case '7':
+		s.savedX, s.savedY = s.x, s.y
+	case '8':
+		s.x, s.y = s.savedX, s.savedY
+	case 'M':
+		if s.y > 0 {
+			s.y--
+		} else {
+			s.scrollDown(0, 1)
+		}
+	case 'c':
+		s.erase(0, len(s.cells))
+		s.x, s.y, s.style = 0, 0, Style{}
+	default:
+		i := 1
+		for i < len(data) && data[i] >= 0x20 && data[i] <= 0x2f {
+			i++
+		}
+		if i == len(data) {
+			return 0
+		}
+		return i + 1
+	}
+	return 2
+}
+
+func (s *Screen) csi(params string, final byte) {
+	private := strings.HasPrefix(params, "?")
+	args := parseParams(strings.TrimLeft(params, "?<=>"))
+	arg := func(i, def int) int {
+		if i < len(args) && args[i] > 0 {
+			return args[i]
+		}
+		return def
+	}
+
+	if private {
+		switch final {
+		case 'h', 'l':
+			for _, n := range args {
+				s.setMode(n, final == 'h')
+			}
+		}
+		return
+	}
+
+	if s.x >= s.width {
+		s.x = s.width - 1
+	}
+
+	switch final {
+	case 'A':
+		s.y -= arg(0, 1)
+	case 'B':
+		s.y += arg(0, 1)
+	case 'C':
+		s.x += arg(0, 1)
+	case 'D':
+		s.x -= arg(0, 1)
+	case 'E':
+		s.y += arg(0, 1)
+		s.x = 0
+	case 'F':
+		s.y -= arg(0, 1)
+		s.x = 0
+	case 'G':
+		s.x = arg(0, 1) - 1
+	case 'd':
+		s.y = arg(0, 1) - 1
+	case 'H', 'f':
+		s.y = arg(0, 1) - 1
+		s.x = arg(1, 1) - 1
+	case 'J':
+		pos := s.y*s.width + s.x
+		switch arg(0, 0) {
+		case 0:
+			s.erase(pos, len(s.cells))
+		case 1:
+			s.erase(0, pos+1)
+		default:
+			s.erase(0, len(s.cells))
+		}
+	case 'K':
+		start := s.y * s.width
+		switch arg(0, 0) {
+		case 0:
+			s.erase(start+s.x, start+s.width)
+		case 1:
+			s.erase(start, start+s.x+1)
+		default:
+			s.erase(start, start+s.width)
+		}
+	case 'L':
+		s.scrollDown(s.y, arg(0, 1))
+	case 'M':
+		s.scrollUp(s.y, arg(0, 1))
+	case 'S':
+		s.scrollUp(0, arg(0, 1))
+	case 'T':
+		s.scrollDown(0, arg(0, 1))
+	case 's':
+		s.savedX, s.savedY = s.x, s.y
+	case 'u':
+		s.x, s.y = s.savedX, s.savedY
+	case 'm':
+		s.sgr(params)
+	}
+
+	s.x = clamp(s.x, 0, s.width-1)
+	s.y = clamp(s.y, 0, s.height-1)
+}
+
+func (s *Screen) setMode(n int, on bool) {
+	s.modes[n] = on
+	switch n {
+	case 25:
+		s.cursorHidden = !on
+	case 1049, 1047, 47:
+		if on && s.main == nil {
+			s.main = append([]Cell(nil), s.cells...)
+			s.savedX, s.savedY = s.x, s.y
+			s.erase(0, len(s.cells))
+		} else if !on && s.main != nil {
+			copy(s.cells, s.main)
+			s.main = nil
+			s.x, s.y = s.savedX, s.savedY
+		}
+	}
+}
+
+func (s *Screen) sgr(params string) {
+	fields := strings.Split(params, ";")
+	for i := 0; i < len(fields); i++ {
+		n, _ := strconv.Atoi(fields[i])
+		switch {
+		case n == 0:
+			s.style = Style{}
+		case n == 1:
+			s.style.Bold = true
+		case n == 2:
+			s.style.Dim = true
+		case n == 3:
+			s.style.Italic = true
+		case n == 4:
+			s.style.Underline = true
+		case n == 5:
+			s.style.Blink = true
+		case n == 7:
+			s.style.Reverse = true
+		case n == 9:
+			s.style.Strike = true
+		case n == 22:
+			s.style.Bold, s.style.Dim = false, false
+		case n == 23:
+			s.style.Italic = false
+		case n == 24:
+			s.style.Underline = false
+		case n == 25:
+			s.style.Blink = false
+		case n == 27:
+			s.style.Reverse = false
+		case n == 29:
+			s.style.Strike = false
+		case (n >= 30 && n <= 37) || (n >= 90 && n <= 97):
+			s.style.Fg = fields[i]
+		case n == 39:
+			s.style.Fg = ""
+		case (n >= 40 && n <= 47) || (n >= 100 && n <= 107):
+			s.style.Bg = fields[i]
+		case n == 49:
+			s.style.Bg = ""
+		case n == 38 || n == 48:
+			size := 0
+			if i+1 < len(fields) {
+				switch fields[i+1] {
+				case "5":
+					size = 2
+				case "2":
+					size = 4
+				}
+			}
+			if size == 0 || i+size >= len(fields) {
+				return
+			}
+			color := strings.Join(fields[i:i+size+1], ";")
+			if n == 38 {
+				s.style.Fg = color
+			} else {
+				s.style.Bg = color
+			}
+			i += size
+		}
+	}
+}
+
+func parseParams(params string) []int {
+	if params == "" {
+		return nil
+	}
+	fields := strings.Split(params, ";")
+	args := make([]int, len(fields))
+	for i, f := range fields {
+		args[i], _ = strconv.Atoi(f)
+	}
+	return args
+}
+
+func clamp(v, lo, hi int) int {
+	if v > hi {
+		v = hi
+	}
+	if v < lo {
+		v = lo
+	}
+	return v
+}
@@ -0,0 +1,139 @@
+package termxtest_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/vynazevedo/termx/termxtest"
+)
+
+func TestScreenWrite(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []string
+		want   []string
+	}{
+		{"text and line feeds", []string{"one\ntwo\r\nthree"}, []string{"one", "two", "three"}},
+		{"carriage return", []string{"hello\rj"}, []string{"jello"}},
+		{"cursor position", []string{"\033[2;3Hx\033[1;1Hy"}, []string{"y", "  x"}},
+		{"relative moves", []string{"\033[2Bab\033[Ac\033[3Dd"}, []string{"", "d c", "ab"}},
+		{"erase line", []string{"abcdef\033[1;3H\033[K"}, []string{"ab"}},
+		{"erase line start", []string{"abcdef\033[1;3H\033[1K"}, []string{"   def"}},
+		{"erase below", []string{"aaa\nbbb\nccc\033[2;2H\033[J"}, []string{"aaa", "b", ""}},
+		{"erase screen", []string{"aaa\nbbb\033[2J"}, []string{"", ""}},
+		{"wrap at the margin", []string{"abcdefghijkl"}, []string{"abcdefghij", "kl"}},
+		{"scroll", []string{"1\n2\n3\n4\n5"}, []string{"2", "3", "4", "5"}},
+		{"wide characters", []string{"日本語", "\033[1;2Hx"}, []string{" x本語"}},
+		{"combining marks", []string{"é!"}, []string{"é!"}},
+		{"sequence split across writes", []string{"\033[2", ";4Hz"}, []string{"", "   z"}},
+		{"save and restore", []string{"ab\0337\033[3;1Hc\0338d"}, []string{"abd", "", "c"}},
+		{"osc ignored", []string{"\033]0;title\aok"}, []string{"ok"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := termxtest.NewScreen(10, 4)
+			for _, w := range tt.writes {
+				io.WriteString(s, w)
+			}
+			for y, want := range tt.want {
+				s.AssertLine(t, y, want)
+			}
+		})
+	}
+}
+
+func TestScreenStyles(t *testing.T) {
+	tests := []struct {
+		seq  string
+		want termxtest.Style
+	}{
+		{"", termxtest.Style{}},
+		{"\033[1;4m", termxtest.Style{Bold: true, Underline: true}},
+		{"\033[1m\033[22m", termxtest.Style{}},
+		{"\033[31;42m", termxtest.Style{Fg: "31", Bg: "42"}},
+		{"\033[38;5;202m", termxtest.Style{Fg: "38;5;202"}},
+		{"\033[1;38;2;1;2;3;48;5;9;7m", termxtest.Style{Fg: "38;2;1;2;3", Bg: "48;5;9", Bold: true, Reverse: true}},
+		{"\033[31m\033[39m", termxtest.Style{}},
+		{"\033[3;9m\033[0m", termxtest.Style{}},
+	}
+	for _, tt := range tests {
+		s := termxtest.NewScreen(10, 2)
+		io.WriteString(s, tt.seq+"x")
+		if got := s.Cell(0, 0).Style; got != tt.want {
+			t.Errorf("style after %q = %+v, want %+v", tt.seq, got, tt.want)
+		}
+	}
+}
+
+func TestScreenModes(t *testing.T) {
+	s := termxtest.NewScreen(10, 3)
+	io.WriteString(s, "main\033[?1049h\033[?25l\033[?1000;1006h")
+	if !s.AltScreen() || s.CursorVisible() || !s.Mode(1000) || !s.Mode(1006) {
+		t.Errorf("alt screen %v, cursor visible %v, mouse modes %v %v after enabling them",
+			s.AltScreen(), s.CursorVisible(), s.Mode(1000), s.Mode(1006))
+	}
+	s.AssertNotContains(t, "main")
+	io.WriteString(s, "alt")
+	s.AssertContains(t, "alt")
+
+	io.WriteString(s, "\033[?1049l\033[?25h\033[?1000;1006l")
+	if s.AltScreen() || !s.CursorVisible() || s.Mode(1000) || s.Mode(1006) {
+		t.Error("modes are still set after disabling them")
+	}
+	s.AssertLine(t, 0, "main")
+	s.AssertNotContains(t, "alt")
+	if x, y := s.Cursor(); x != 4 || y != 0 {
+		t.Errorf("cursor at %d,%d after leaving the alternate screen, want 4,0", x, y)
+	}
+}
+
+func TestScreenResize(t *testing.T) {
+	s := termxtest.NewScreen(10, 3)
+	io.WriteString(s, "abcdefgh\nsecond\nthird")
+	s.Resize(4, 2)
+	if w, h := s.Size(); w != 4 || h != 2 {
+		t.Fatalf("Size() = %d, %d, want 4, 2", w, h)
+	}
+	if got, want := s.Text(), "abcd\nseco"; got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+}
+
+// Scripted input reaches the renderer in order and ends with io.EOF
+func TestTerminalScript(t *testing.T) {
+	term := termxtest.NewTerminal(20, 5)
+	term.Type("hi").Press(termxtest.KeyEnter).Paste("a\nb").Click(3, 1)
+
+	r := term.Renderer().WithMouse()
+	if err := r.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Restore()
+
+	var got []string
+	for {
+		event, err := r.ReadInput()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch {
+		case event.Text != "":
+			got = append(got, "paste:"+event.Text)
+		case event.Rune != 0:
+			got = append(got, string(event.Rune))
+		default:
+			got = append(got, event.Key.String())
+		}
+	}
+	want := []string{"h", "i", "Enter", "paste:a\nb", "Mouse", "Mouse"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("read %q, want %q", got, want)
+	}
+	if term.Pending() != 0 {
+		t.Errorf("Pending() = %d after reading the script", term.Pending())
+	}
+}
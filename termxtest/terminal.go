@@ -0,0 +1,235 @@
+package termxtest
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vynazevedo/termx/colorprofile"
+	"github.com/vynazevedo/termx/renderer"
+)
+
+// Raw input of common keys, for use with Terminal.Press
+const (
+	KeyEnter     = "\r"
+	KeyTab       = "\t"
+	KeyShiftTab  = "\033[Z"
+	KeyBackspace = "\x7f"
+	KeyEscape    = "\033"
+	KeySpace     = " "
+	KeyCtrlC     = "\x03"
+	KeyCtrlD     = "\x04"
+	KeyUp        = "\033[A"
+	KeyDown      = "\033[B"
+	KeyRight     = "\033[C"
+	KeyLeft      = "\033[D"
+	KeyHome      = "\033[H"
+	KeyEnd       = "\033[F"
+	KeyPageUp    = "\033[5~"
+	KeyPageDown  = "\033[6~"
+	KeyDelete    = "\033[3~"
+)
+
+// settle is how long a scripted resize waits for the component to redraw
+// after the input before it
+const settle = time.Second
+
+// Terminal is an emulated terminal for running components in tests. Its
+// renderer draws on Screen and reads the keystrokes queued with Type,
+// Press, Paste and Click, in order.
+type Terminal struct {
+	*Screen
+
+	script    *script
+	renderer  *renderer.Renderer
+	renderers []*renderer.Renderer
+}
+
+// NewTerminal creates a terminal of width columns and height rows with an
+// empty input script. Output uses TrueColor so that styles reach the screen
+// unchanged.
+func NewTerminal(width, height int) *Terminal {
+	t := &Terminal{Screen: NewScreen(width, height)}
+	t.script = &script{terminal: t}
+	t.renderer = t.NewRenderer()
+	return t
+}
+
+// Renderer returns the renderer to pass to a component's WithRenderer
+func (t *Terminal) Renderer() *renderer.Renderer {
+	return t.renderer
+}
+
+// NewRenderer returns another renderer on the terminal, like the one a
+// component creates for itself on the standard streams. It is meant for
+// components run from inside another one, such as a Select opened by a Menu
+// action.
+func (t *Terminal) NewRenderer() *renderer.Renderer {
+	r := renderer.NewWithIO(t.script, t.Screen, func() (int, int, error) {
+		w, h := t.Screen.Size()
+		return w, h, nil
+	}).WithProfile(colorprofile.TrueColor)
+
+	t.script.mu.Lock()
+	t.renderers = append(t.renderers, r)
+	t.script.mu.Unlock()
+	return r
+}
+
+// Type queues text as if it was typed, in a single read
+func (t *Terminal) Type(text string) *Terminal {
+	t.script.add(step{data: text})
+	return t
+}
+
+// Press queues raw key sequences such as KeyEnter, each in its own read.
+// Input that ends in ESC, such as KeyEscape, is followed by a pause longer
+// than renderer.EscapeTimeout, so that it is not mistaken for the start of
+// a sequence.
+func (t *Terminal) Press(keys ...string) *Terminal {
+	for _, key := range keys {
+		t.script.add(step{data: key})
+	}
+	return t
+}
+
+// Paste queues text as a bracketed paste
+func (t *Terminal) Paste(text string) *Terminal {
+	t.script.add(step{data: "\033[200~" + text + "\033[201~"})
+	return t
+}
+
+// Click queues a left click at column x, row y, counted from zero
+func (t *Terminal) Click(x, y int) *Terminal {
+	press := fmt.Sprintf("\033[<0;%d;%dM", x+1, y+1)
+	release := fmt.Sprintf("\033[<0;%d;%dm", x+1, y+1)
+	t.script.add(step{data: press + release})
+	return t
+}
+
+// Wheel queues a wheel movement at column x, row y; up selects the
+// direction
+func (t *Terminal) Wheel(x, y int, up bool) *Terminal {
+	button := 65
+	if up {
+		button = 64
+	}
+	t.script.add(step{data: fmt.Sprintf("\033[<%d;%d;%dM", button, x+1, y+1)})
+	return t
+}
+
+// Resize queues a change of the terminal size. It takes effect once the
+// component has redrawn after the input queued before it, and the input
+// queued after it is delivered once the component has redrawn for the new
+// size.
+func (t *Terminal) Resize(width, height int) *Terminal {
+	t.script.add(step{resize: true, width: width, height: height})
+	return t
+}
+
+// Pending returns the number of queued steps that have not been read yet
+func (t *Terminal) Pending() int {
+	t.script.mu.Lock()
+	defer t.script.mu.Unlock()
+	return len(t.script.steps)
+}
+
+// waitWrite blocks until the screen received a write after the count
+// mark, or until timeout elapses
+func (s *Screen) waitWrite(mark int, timeout time.Duration) {
+	timer := time.AfterFunc(timeout, func() {
+		s.mu.Lock()
+		s.written.Broadcast()
+		s.mu.Unlock()
+	})
+	defer timer.Stop()
+
+	deadline := time.Now().Add(timeout)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for s.writes <= mark && time.Now().Before(deadline) {
+		s.written.Wait()
+	}
+}
+
+func (s *Screen) writeCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.writes
+}
+
+type step struct {
+	data   string
+	resize bool
+	width  int
+	height int
+}
+
+// script is the input stream of a Terminal
+type script struct {
+	terminal *Terminal
+
+	mu      sync.Mutex
+	steps   []step
+	mark    int  // screen writes seen when the last input was delivered
+	escaped bool // the last input ended in ESC
+}
+
+func (s *script) add(st step) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.steps = append(s.steps, st)
+}
+
+func (s *script) Read(buf []byte) (int, error) {
+	screen := s.terminal.Screen
+	for {
+		s.mu.Lock()
+		if len(s.steps) == 0 {
+			s.mu.Unlock()
+			return 0, io.EOF
+		}
+		if s.escaped {
+			// Let the renderer take the ESC as the Esc key before the next
+			// input arrives
+			s.escaped = false
+			s.mu.Unlock()
+			time.Sleep(2 * renderer.EscapeTimeout)
+			continue
+		}
+		st := s.steps[0]
+
+		if st.resize {
+			s.steps = s.steps[1:]
+			mark := s.mark
+			s.mu.Unlock()
+
+			screen.waitWrite(mark, settle)
+			screen.Resize(st.width, st.height)
+			s.mu.Lock()
+			s.mark = screen.writeCount()
+			s.mu.Unlock()
+			s.mu.Lock()
+			renderers := s.terminal.renderers
+			s.mu.Unlock()
+			for _, r := range renderers {
+				r.NotifyResize()
+			}
+			screen.waitWrite(s.mark, settle)
+			continue
+		}
+
+		n := copy(buf, st.data)
+		if n < len(st.data) {
+			s.steps[0].data = st.data[n:]
+		} else {
+			s.steps = s.steps[1:]
+			s.escaped = strings.HasSuffix(st.data, "\033")
+		}
+		s.mark = screen.writeCount()
+		s.mu.Unlock()
+		return n, nil
+	}
+}
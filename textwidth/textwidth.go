@@ -0,0 +1,288 @@
+// Package textwidth measures how many terminal cells text occupies.
+//
+// Widths follow the Unicode East Asian Width property: wide and fullwidth
+// characters (CJK, Hangul, most emoji) take two cells, combining marks and
+// other zero-width characters take none. Text is measured per grapheme
+// cluster, so an emoji ZWJ sequence, a flag or a letter followed by an
+// accent counts as a single character.
+//
+// The functions in this package work on plain text. Strings carrying ANSI
+// escape sequences should be measured with the renderer package helpers.
+package textwidth
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	zeroWidthJoiner = 0x200D
+	textPresent     = 0xFE0E
+	emojiPresent    = 0xFE0F
+)
+
+// RuneWidth returns the number of cells r occupies when printed on its own
+func RuneWidth(r rune) int {
+	switch {
+	case r == 0:
+		return 0
+	case r < 32 || (r >= 0x7f && r < 0xa0):
+		return 0
+	case r < 0x300:
+		return 1
+	case isZeroWidth(r):
+		return 0
+	case inTable(r, wide):
+		return 2
+	}
+	return 1
+}
+
+func isZeroWidth(r rune) bool {
+	switch {
+	case r == zeroWidthJoiner || r == 0x200B || r == 0x200C || r == 0x2060 || r == 0xFEFF:
+		return true
+	case r >= 0xFE00 && r <= 0xFE0F:
+		return true
+	case r >= 0xE0000 && r <= 0xE0FFF:
+		return true
+	case r >= 0x1160 && r <= 0x11FF:
+		// Hangul medial vowels and final consonants join the preceding syllable
+		return true
+	}
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf)
+}
+
+// extends reports whether r continues the grapheme cluster before it
+func extends(r rune) bool {
+	switch {
+	case r >= 0x1F3FB && r <= 0x1F3FF:
+		// Emoji skin tone modifiers
+		return true
+	case unicode.Is(unicode.Mc, r):
+		return true
+	}
+	return isZeroWidth(r)
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// FirstCluster returns the leading grapheme cluster of s and its width
+func FirstCluster(s string) (string, int) {
+	if s == "" {
+		return "", 0
+	}
+
+	base, size := utf8.DecodeRuneInString(s)
+	width := RuneWidth(base)
+	end := size
+
+	if isRegionalIndicator(base) {
+		if next, n := utf8.DecodeRuneInString(s[end:]); isRegionalIndicator(next) {
+			return s[:end+n], 2
+		}
+	}
+
+	prev := base
+	for end < len(s) {
+		r, n := utf8.DecodeRuneInString(s[end:])
+		if prev != zeroWidthJoiner && !extends(r) {
+			break
+		}
+		switch {
+		case r == emojiPresent && width == 1:
+			width = 2
+		case r == textPresent && width == 2:
+			width = 1
+		}
+		prev = r
+		end += n
+	}
+	return s[:end], width
+}
+
+// String returns the number of cells s occupies
+func String(s string) int {
+	width := 0
+	for s != "" {
+		cluster, w := FirstCluster(s)
+		width += w
+		s = s[len(cluster):]
+	}
+	return width
+}
+
+// Truncate shortens s to at most width cells. When s has to be cut, tail
+// (such as "..." or "…") is appended and counted in the width. Wide
+// characters are never split in half.
+func Truncate(s string, width int, tail string) string {
+	if String(s) <= width {
+		return s
+	}
+
+	if width <= 0 {
+		return ""
+	}
+
+	limit := width - String(tail)
+	if limit < 0 {
+		return Truncate(tail, width, "")
+	}
+
+	var out strings.Builder
+	used := 0
+	for s != "" {
+		cluster, w := FirstCluster(s)
+		if used+w > limit {
+			break
+		}
+		out.WriteString(cluster)
+		used += w
+		s = s[len(cluster):]
+	}
+	out.WriteString(tail)
+	return out.String()
+}
+
+// PadRight appends spaces to s until it is width cells wide
+func PadRight(s string, width int) string {
+	if n := width - String(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
+
+// PadLeft prepends spaces to s until it is width cells wide
+func PadLeft(s string, width int) string {
+	if n := width - String(s); n > 0 {
+		return strings.Repeat(" ", n) + s
+	}
+	return s
+}
+
+// Center surrounds s with spaces so it sits in the middle of width cells.
+// An odd remaining cell goes to the right side.
+func Center(s string, width int) string {
+	n := width - String(s)
+	if n <= 0 {
+		return s
+	}
+	return strings.Repeat(" ", n/2) + s + strings.Repeat(" ", n-n/2)
+}
+
+// inTable reports whether r falls in one of the sorted ranges of table
+func inTable(r rune, table [][2]rune) bool {
+	lo, hi := 0, len(table)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		switch {
+		case r < table[mid][0]:
+			hi = mid
+		case r > table[mid][1]:
+			lo = mid + 1
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// wide lists the East Asian Wide and Fullwidth ranges, including the
+// emoji that are presented as pictographs by default
+var wide = [][2]rune{
+	{0x1100, 0x115F},
+	{0x231A, 0x231B},
+	{0x2329, 0x232A},
+	{0x23E9, 0x23EC},
+	{0x23F0, 0x23F0},
+	{0x23F3, 0x23F3},
+	{0x25FD, 0x25FE},
+	{0x2614, 0x2615},
+	{0x2648, 0x2653},
+	{0x267F, 0x267F},
+	{0x2693, 0x2693},
+	{0x26A1, 0x26A1},
+	{0x26AA, 0x26AB},
+	{0x26BD, 0x26BE},
+	{0x26C4, 0x26C5},
+	{0x26CE, 0x26CE},
+	{0x26D4, 0x26D4},
+	{0x26EA, 0x26EA},
+	{0x26F2, 0x26F3},
+	{0x26F5, 0x26F5},
+	{0x26FA, 0x26FA},
+	{0x26FD, 0x26FD},
+	{0x2705, 0x2705},
+	{0x270A, 0x270B},
+	{0x2728, 0x2728},
+	{0x274C, 0x274C},
+	{0x274E, 0x274E},
+	{0x2753, 0x2755},
+	{0x2757, 0x2757},
+	{0x2795, 0x2797},
+	{0x27B0, 0x27B0},
+	{0x27BF, 0x27BF},
+	{0x2B1B, 0x2B1C},
+	{0x2B50, 0x2B50},
+	{0x2B55, 0x2B55},
+	{0x2E80, 0x303E},
+	{0x3041, 0x33FF},
+	{0x3400, 0x4DBF},
+	{0x4E00, 0x9FFF},
+	{0xA000, 0xA4CF},
+	{0xA960, 0xA97F},
+	{0xAC00, 0xD7A3},
+	{0xF900, 0xFAFF},
+	{0xFE10, 0xFE19},
+	{0xFE30, 0xFE6F},
+	{0xFF00, 0xFF60},
+	{0xFFE0, 0xFFE6},
+	{0x16FE0, 0x16FE4},
+	{0x17000, 0x18AFF},
+	{0x1B000, 0x1B2FF},
+	{0x1F004, 0x1F004},
+	{0x1F0CF, 0x1F0CF},
+	{0x1F18E, 0x1F18E},
+	{0x1F191, 0x1F19A},
+	{0x1F200, 0x1F202},
+	{0x1F210, 0x1F23B},
+	{0x1F240, 0x1F248},
+	{0x1F250, 0x1F251},
+	{0x1F260, 0x1F265},
+	{0x1F300, 0x1F320},
+	{0x1F32D, 0x1F335},
+	{0x1F337, 0x1F37C},
+	{0x1F37E, 0x1F393},
+	{0x1F3A0, 0x1F3CA},
+	{0x1F3CF, 0x1F3D3},
+	{0x1F3E0, 0x1F3F0},
+	{0x1F3F4, 0x1F3F4},
+	{0x1F3F8, 0x1F43E},
+	{0x1F440, 0x1F440},
+	{0x1F442, 0x1F4FC},
+	{0x1F4FF, 0x1F53D},
+	{0x1F54B, 0x1F54E},
+	{0x1F550, 0x1F567},
+	{0x1F57A, 0x1F57A},
+	{0x1F595, 0x1F596},
+	{0x1F5A4, 0x1F5A4},
+	{0x1F5FB, 0x1F64F},
+	{0x1F680, 0x1F6C5},
+	{0x1F6CC, 0x1F6CC},
+	{0x1F6D0, 0x1F6D2},
+	{0x1F6D5, 0x1F6D7},
+	{0x1F6DC, 0x1F6DF},
+	{0x1F6EB, 0x1F6EC},
+	{0x1F6F4, 0x1F6FC},
+	{0x1F7E0, 0x1F7EB},
+	{0x1F7F0, 0x1F7F0},
+	{0x1F90C, 0x1F93A},
+	{0x1F93C, 0x1F945},
+	{0x1F947, 0x1F9FF},
+	{0x1FA70, 0x1FAFF},
+	{0x20000, 0x2FFFD},
+	{0x30000, 0x3FFFD},
+}
@@ -0,0 +1,73 @@
+package textwidth
+
+import "testing"
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"日本", 4},
+		{"e\u0301", 1}, // e + combining acute accent
+		{"👍", 2},
+		{"👨‍👩‍👧", 2},        // ZWJ family
+		{"🇧🇷", 2},           // flag
+		{"\u2665\ufe0f", 2}, // heart with emoji presentation
+		{"a\tb", 2},         // control characters take no cell
+	}
+	for _, tt := range tests {
+		if got := String(tt.in); got != tt.want {
+			t.Errorf("String(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		tail  string
+		want  string
+	}{
+		{"hello", 10, "...", "hello"},
+		{"hello", 5, "...", "hello"},
+		{"hello world", 8, "...", "hello..."},
+		{"hello", 2, "...", ".."},
+		{"日本語", 5, "", "日本"},
+		{"日本語", 5, "…", "日本…"},
+		{"日本語", 4, "…", "日…"},
+		{"hello", 0, "...", ""},
+		{"hello", -1, "...", ""},
+		{"", -4, "", ""},
+	}
+	for _, tt := range tests {
+		if got := Truncate(tt.in, tt.width, tt.tail); got != tt.want {
+			t.Errorf("Truncate(%q, %d, %q) = %q, want %q", tt.in, tt.width, tt.tail, got, tt.want)
+		}
+	}
+}
+
+func TestPad(t *testing.T) {
+	tests := []struct {
+		in               string
+		width            int
+		right, left, mid string
+	}{
+		{"ab", 5, "ab   ", "   ab", " ab  "},
+		{"日", 4, "日  ", "  日", " 日 "},
+		{"abcdef", 3, "abcdef", "abcdef", "abcdef"},
+	}
+	for _, tt := range tests {
+		if got := PadRight(tt.in, tt.width); got != tt.right {
+			t.Errorf("PadRight(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.right)
+		}
+		if got := PadLeft(tt.in, tt.width); got != tt.left {
+			t.Errorf("PadLeft(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.left)
+		}
+		if got := Center(tt.in, tt.width); got != tt.mid {
+			t.Errorf("Center(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.mid)
+		}
+	}
+}
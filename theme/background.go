@@ -0,0 +1,53 @@
+package theme
+
+import (
+	"os"
+	"sync"
+
+	"github.com/vynazevedo/termx/colorprofile"
+)
+
+// background is the background set with SetDarkBackground or found by
+// DetectBackground, if any
+var background struct {
+	sync.Mutex
+	set  bool
+	dark bool
+}
+
+// DarkBackground reports whether styles are drawn for a dark background. It
+// never asks the terminal: unless SetDarkBackground or DetectBackground
+// decided it, the answer a renderer got when it was first initialized on
+// the standard streams is used, and a dark background is assumed before
+// that or when the terminal did not tell.
+func DarkBackground() bool {
+	background.Lock()
+	set, dark := background.set, background.dark
+	background.Unlock()
+	if set {
+		return dark
+	}
+	dark, ok := colorprofile.CachedBackground()
+	return dark || !ok
+}
+
+// SetDarkBackground makes styles use their dark or light colors
+func SetDarkBackground(dark bool) {
+	background.Lock()
+	defer background.Unlock()
+	background.set, background.dark = true, dark
+}
+
+// DetectBackground asks the terminal for its background color again,
+// falling back to COLORFGBG, and makes styles use the matching colors. It
+// returns whether the background is dark; without an answer it stays as it
+// was.
+//
+// It reads the answer from standard input, so it must not be called while a
+// component runs.
+func DetectBackground() bool {
+	if dark, ok := colorprofile.DarkBackground(os.Stdin, os.Stdout, colorprofile.BackgroundTimeout); ok {
+		SetDarkBackground(dark)
+	}
+	return DarkBackground()
+}
@@ -0,0 +1,56 @@
+package theme
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPTY returns the master and slave sides of a new pseudo terminal
+func openPTY(t *testing.T) (master, slave *os.File) {
+	t.Helper()
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		t.Skipf("no pseudo terminals: %v", err)
+	}
+	t.Cleanup(func() { master.Close() })
+
+	fd := int(master.Fd())
+	if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		t.Fatal(err)
+	}
+	n, err := unix.IoctlGetInt(fd, unix.TIOCGPTN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slave, err = os.OpenFile("/dev/pts/"+strconv.Itoa(n), os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { slave.Close() })
+	return master, slave
+}
+
+// Drawing a style must not ask the terminal for its background color: the
+// program may be reading the input, and the answer would reach it as keys
+func TestSequenceDoesNotQueryTerminal(t *testing.T) {
+	master, slave := openPTY(t)
+	stdin, stdout := os.Stdin, os.Stdout
+	os.Stdin, os.Stdout = slave, slave
+	defer func() { os.Stdin, os.Stdout = stdin, stdout }()
+
+	background.Lock()
+	background.set = false
+	background.Unlock()
+	defer SetDarkBackground(true)
+
+	if got := (Style{Fg: White, LightFg: Black}).Sequence(); got != "\033[37m" {
+		t.Errorf("Sequence() = %q, want the dark color %q before the background is known", got, "\033[37m")
+	}
+	fds := []unix.PollFd{{Fd: int32(master.Fd()), Events: unix.POLLIN}}
+	if n, _ := unix.Poll(fds, 200); n > 0 {
+		t.Error("Sequence() wrote a query to the terminal")
+	}
+}
@@ -0,0 +1,39 @@
+package theme_test
+
+import (
+	"testing"
+
+	"github.com/vynazevedo/termx/theme"
+)
+
+func TestAdaptiveColors(t *testing.T) {
+	defer theme.SetDarkBackground(true)
+
+	tests := []struct {
+		dark      bool
+		text      string
+		solarized *theme.Theme
+	}{
+		{true, "\033[37m", theme.SolarizedDark},
+		{false, "\033[30m", theme.SolarizedLight},
+	}
+	for _, tt := range tests {
+		theme.SetDarkBackground(tt.dark)
+		if got := theme.Default.Text.Sequence(); got != tt.text {
+			t.Errorf("dark=%v: Default.Text.Sequence() = %q, want %q", tt.dark, got, tt.text)
+		}
+		if got, _ := theme.Lookup("solarized"); got != tt.solarized {
+			t.Errorf("dark=%v: Lookup(solarized) returned the wrong variant", tt.dark)
+		}
+	}
+}
+
+func TestAdapt(t *testing.T) {
+	s := theme.Style{Fg: theme.White, LightFg: theme.Black, Bg: theme.Blue}
+	if got, want := s.Adapt(true), (theme.Style{Fg: theme.White, Bg: theme.Blue}); got != want {
+		t.Errorf("Adapt(true) = %+v, want %+v", got, want)
+	}
+	if got, want := s.Adapt(false), (theme.Style{Fg: theme.Black, Bg: theme.Blue}); got != want {
+		t.Errorf("Adapt(false) = %+v, want %+v", got, want)
+	}
+}
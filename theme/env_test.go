@@ -0,0 +1,71 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setEnv sets TERMX_THEME to value and makes the next Current load it again
+func setEnv(t *testing.T, value string) {
+	t.Helper()
+	t.Setenv(EnvVar, value)
+	resetEnv := func() {
+		env.Lock()
+		env.loaded, env.theme, env.err = false, nil, nil
+		env.Unlock()
+	}
+	resetEnv()
+	t.Cleanup(resetEnv)
+}
+
+func TestCurrentAppliesEnv(t *testing.T) {
+	defer Set(Default)
+
+	setEnv(t, "Nord")
+	Set(Dracula)
+	if Current() != Nord {
+		t.Error("Current() is not the theme named by TERMX_THEME")
+	}
+
+	setEnv(t, "")
+	if Current() != Dracula {
+		t.Error("Current() is not the theme given to Set when TERMX_THEME is empty")
+	}
+
+	path := filepath.Join(t.TempDir(), "company.yaml")
+	if err := os.WriteFile(path, []byte("primary: \"#ff8800\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	setEnv(t, path)
+	if got := Current().Primary.Fg; got != Hex(0xff8800) {
+		t.Errorf("Current().Primary.Fg = %v, want #ff8800 from %s", got, path)
+	}
+}
+
+func TestSetFromEnvReportsErrors(t *testing.T) {
+	defer Set(Default)
+	Set(Default)
+
+	tests := []struct {
+		value string
+		err   string
+	}{
+		{"nord", ""},
+		{"nrod", `TERMX_THEME: unknown theme "nrod"`},
+		{"missing.toml", "TERMX_THEME: open missing.toml"},
+	}
+	for _, tt := range tests {
+		setEnv(t, tt.value)
+		err := SetFromEnv()
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("TERMX_THEME=%s: SetFromEnv() error = %v", tt.value, err)
+		case tt.err != "" && (err == nil || !strings.HasPrefix(err.Error(), tt.err)):
+			t.Errorf("TERMX_THEME=%s: SetFromEnv() error = %v, want %s...", tt.value, err, tt.err)
+		case tt.err != "" && Current() != Default:
+			t.Errorf("TERMX_THEME=%s: a theme that failed to load replaced the current one", tt.value)
+		}
+	}
+}
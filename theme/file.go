@@ -0,0 +1,312 @@
+package theme
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// EnvVar names the theme to use: the name of a registered theme, such as
+// "nord", or the path of a theme file
+const EnvVar = "TERMX_THEME"
+
+// tokens maps the names used in theme files to the fields of a Theme
+var tokens = map[string]func(t *Theme) *Style{
+	"primary":     func(t *Theme) *Style { return &t.Primary },
+	"secondary":   func(t *Theme) *Style { return &t.Secondary },
+	"success":     func(t *Theme) *Style { return &t.Success },
+	"error":       func(t *Theme) *Style { return &t.Error },
+	"warning":     func(t *Theme) *Style { return &t.Warning },
+	"info":        func(t *Theme) *Style { return &t.Info },
+	"text":        func(t *Theme) *Style { return &t.Text },
+	"text_dim":    func(t *Theme) *Style { return &t.TextDim },
+	"background":  func(t *Theme) *Style { return &t.Background },
+	"border":      func(t *Theme) *Style { return &t.Border },
+	"focus":       func(t *Theme) *Style { return &t.Focus },
+	"cursor":      func(t *Theme) *Style { return &t.Cursor },
+	"selected":    func(t *Theme) *Style { return &t.Selected },
+	"placeholder": func(t *Theme) *Style { return &t.Placeholder },
+	"muted":       func(t *Theme) *Style { return &t.Muted },
+	"disabled":    func(t *Theme) *Style { return &t.Disabled },
+	"match":       func(t *Theme) *Style { return &t.Match },
+	"help_key":    func(t *Theme) *Style { return &t.HelpKey },
+	"help_text":   func(t *Theme) *Style { return &t.HelpText },
+}
+
+// attributes maps the style attributes of theme files to their flags
+var attributes = map[string]Attr{
+	"bold":          AttrBold,
+	"dim":           AttrDim,
+	"italic":        AttrItalic,
+	"underline":     AttrUnderline,
+	"reverse":       AttrReverse,
+	"strikethrough": AttrStrikethrough,
+}
+
+// Load reads a theme file. The format is chosen by the extension: .json,
+// .yaml, .yml or .toml.
+//
+// A theme file sets tokens by name (primary, text_dim, selected, ...). A
+// token is either a color, which sets the foreground, or a table with the
+// fields fg, bg and the attributes bold, dim, italic, underline, reverse and
+// strikethrough. Colors are names such as "cyan" or "bright_red", hex
+// "#RRGGBB" values or 256-color indices; fg and bg may also be tables with
+// a light and a dark color, chosen by DarkBackground(). Tokens the file
+// leaves out keep their value from Default. In YAML:
+//
+//	primary: cyan
+//	selected:
+//	  fg: black
+//	  bg: "#5fafd7"
+//	  bold: true
+//	text:
+//	  fg:
+//	    light: black
+//	    dark: white
+func Load(path string) (*Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	t, err := Parse(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return t, nil
+}
+
+// Parse reads a theme in the given format, "json", "yaml" (or "yml") or
+// "toml", as described for Load
+func Parse(data []byte, format string) (*Theme, error) {
+	var doc map[string]any
+	var err error
+
+	switch format {
+	case "json":
+		err = json.Unmarshal(data, &doc)
+		if err != nil {
+			err = fmt.Errorf("theme: %w", err)
+		}
+	case "yaml", "yml":
+		doc, err = parseYAML(string(data))
+	case "toml":
+		doc, err = parseTOML(string(data))
+	default:
+		return nil, fmt.Errorf("theme: unknown format %q, expected json, yaml or toml", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	t := *Default
+	if err := t.apply(doc); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// FromEnv returns the theme named by TERMX_THEME, or nil if the variable is
+// not set. Registered names take precedence over files. Adaptive themes are
+// resolved for DarkBackground().
+func FromEnv() (*Theme, error) {
+	name := os.Getenv(EnvVar)
+	if name == "" {
+		return nil, nil
+	}
+	if t, ok := Lookup(name); ok {
+		return t, nil
+	}
+	if filepath.Ext(name) == "" {
+		return nil, fmt.Errorf("%s: unknown theme %q, expected a theme file or one of %s", EnvVar, name, strings.Join(Names(), ", "))
+	}
+
+	t, err := Load(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", EnvVar, err)
+	}
+	return t, nil
+}
+
+// env is the theme chosen with TERMX_THEME, loaded once by loadEnv
+var env struct {
+	sync.Mutex
+	loaded bool
+	theme  *Theme
+	err    error
+}
+
+// loadEnv returns the theme named by TERMX_THEME, loading it on the first
+// call. With report set, an error loading it is written to standard error.
+func loadEnv(report bool) (*Theme, error) {
+	env.Lock()
+	defer env.Unlock()
+
+	if !env.loaded {
+		env.loaded = true
+		env.theme, env.err = FromEnv()
+		if env.err != nil && report {
+			fmt.Fprintf(os.Stderr, "termx: %v\n", env.err)
+		}
+	}
+	return env.theme, env.err
+}
+
+// SetFromEnv applies TERMX_THEME now and returns the error loading it,
+// which Current would otherwise write to standard error. Programs call it
+// at startup to report the error their own way; the current theme is left
+// alone when the variable is not set or the theme cannot be loaded.
+func SetFromEnv() error {
+	_, err := loadEnv(false)
+	return err
+}
+
+// apply sets the tokens of doc on t
+func (t *Theme) apply(doc map[string]any) error {
+	for _, name := range sortedKeys(doc) {
+		field, ok := tokens[name]
+		if !ok {
+			return fmt.Errorf("theme: unknown token %q, expected one of %s", name, strings.Join(sortedKeys(tokens), ", "))
+		}
+		s, err := parseToken(name, doc[name])
+		if err != nil {
+			return err
+		}
+		*field(t) = s
+	}
+	return nil
+}
+
+// parseToken converts the value of a token to a Style
+func parseToken(name string, value any) (Style, error) {
+	if _, ok := value.(map[string]any); !ok {
+		fg, err := ParseColor(value)
+		if err != nil {
+			return Style{}, fmt.Errorf("theme: %s: %w", name, err)
+		}
+		return Style{Fg: fg}, nil
+	}
+
+	var s Style
+	fields := value.(map[string]any)
+	for _, key := range sortedKeys(fields) {
+		path := name + "." + key
+		v := fields[key]
+
+		switch key {
+		case "fg", "bg":
+			dark, light, err := parseAdaptive(path, v)
+			if err != nil {
+				return Style{}, err
+			}
+			if key == "fg" {
+				s.Fg, s.LightFg = dark, light
+			} else {
+				s.Bg, s.LightBg = dark, light
+			}
+		default:
+			attr, ok := attributes[key]
+			if !ok {
+				return Style{}, fmt.Errorf("theme: %s: unknown field, expected fg, bg or one of %s", path, strings.Join(sortedKeys(attributes), ", "))
+			}
+			on, ok := v.(bool)
+			if !ok {
+				return Style{}, fmt.Errorf("theme: %s: expected true or false, got %v", path, v)
+			}
+			if on {
+				s.Attrs |= attr
+			}
+		}
+	}
+	return s, nil
+}
+
+// parseAdaptive converts a color, or a table with the colors for light and
+// dark backgrounds, to the color for dark backgrounds and the light variant
+func parseAdaptive(path string, value any) (dark, light Color, err error) {
+	variants, ok := value.(map[string]any)
+	if !ok {
+		dark, err = ParseColor(value)
+		if err != nil {
+			return 0, 0, fmt.Errorf("theme: %s: %w", path, err)
+		}
+		return dark, 0, nil
+	}
+
+	for _, key := range sortedKeys(variants) {
+		if key != "light" && key != "dark" {
+			return 0, 0, fmt.Errorf("theme: %s.%s: unknown field, expected light or dark", path, key)
+		}
+		c, err := ParseColor(variants[key])
+		if err != nil {
+			return 0, 0, fmt.Errorf("theme: %s.%s: %w", path, key, err)
+		}
+		if key == "dark" {
+			dark = c
+		} else {
+			light = c
+		}
+	}
+	return dark, light, nil
+}
+
+// ParseColor converts a color name such as "cyan" or "bright_red", a hex
+// "#RRGGBB" value or a 256-color index to a Color. "default" and the empty
+// string are the terminal's default color.
+func ParseColor(value any) (Color, error) {
+	switch v := value.(type) {
+	case string:
+		s := strings.ToLower(strings.TrimSpace(v))
+		for i, name := range ansiNames {
+			if strings.ReplaceAll(s, "-", "_") == name {
+				return ANSI(i), nil
+			}
+		}
+		switch s {
+		case "gray", "grey":
+			return BrightBlack, nil
+		case "", "default", "none":
+			return 0, nil
+		}
+		if strings.HasPrefix(s, "#") {
+			rgb, err := strconv.ParseUint(s[1:], 16, 32)
+			if err != nil || len(s) != 7 {
+				return 0, fmt.Errorf("invalid hex color %q, expected #RRGGBB", v)
+			}
+			return Hex(uint32(rgb)), nil
+		}
+		if n, err := strconv.Atoi(s); err == nil {
+			return ParseColor(n)
+		}
+		return 0, fmt.Errorf("unknown color %q", v)
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, fmt.Errorf("color index %v is not a whole number", v)
+		}
+		return ParseColor(int(v))
+	case int:
+		if v < 0 || v > 255 {
+			return 0, fmt.Errorf("color index %d out of range 0-255", v)
+		}
+		return Indexed(v), nil
+	case nil:
+		return 0, fmt.Errorf("missing color")
+	}
+	return 0, fmt.Errorf("expected a color, got %v", value)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
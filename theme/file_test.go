@@ -0,0 +1,150 @@
+package theme_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vynazevedo/termx/theme"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		in   any
+		want theme.Color
+		err  string
+	}{
+		{"cyan", theme.Cyan, ""},
+		{" Bright_Red ", theme.BrightRed, ""},
+		{"bright-blue", theme.BrightBlue, ""},
+		{"grey", theme.BrightBlack, ""},
+		{"default", 0, ""},
+		{"", 0, ""},
+		{"#5fafd7", theme.Hex(0x5fafd7), ""},
+		{"#FFF", 0, `invalid hex color "#FFF"`},
+		{"#zzzzzz", 0, `invalid hex color "#zzzzzz"`},
+		{"202", theme.Indexed(202), ""},
+		{202, theme.Indexed(202), ""},
+		{float64(33), theme.Indexed(33), ""},
+		{1.5, 0, "color index 1.5 is not a whole number"},
+		{256, 0, "color index 256 out of range 0-255"},
+		{"teal", 0, `unknown color "teal"`},
+		{nil, 0, "missing color"},
+		{true, 0, "expected a color, got true"},
+	}
+	for _, tt := range tests {
+		got, err := theme.ParseColor(tt.in)
+		switch {
+		case tt.err != "" && (err == nil || !strings.HasPrefix(err.Error(), tt.err)):
+			t.Errorf("ParseColor(%#v) error = %v, want %s", tt.in, err, tt.err)
+		case tt.err == "" && err != nil:
+			t.Errorf("ParseColor(%#v) error = %v", tt.in, err)
+		case got != tt.want:
+			t.Errorf("ParseColor(%#v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	want := map[string]theme.Style{
+		"primary":  {Fg: theme.Cyan},
+		"muted":    {Fg: theme.Indexed(244)},
+		"selected": {Fg: theme.Black, Bg: theme.Hex(0x5fafd7), Attrs: theme.AttrBold},
+		"text":     {Fg: theme.White, LightFg: theme.Black},
+	}
+	// The same theme written in each format
+	tests := []struct {
+		format string
+		data   string
+	}{
+		{"json", `{
+			"primary": "cyan",
+			"muted": 244,
+			"selected": {"fg": "black", "bg": "#5fafd7", "bold": true, "underline": false},
+			"text": {"fg": {"light": "black", "dark": "white"}}
+		}`},
+		{"yaml", `# company theme
+primary: cyan
+muted: 244
+selected:
+  fg: black
+  bg: "#5fafd7"   # brand blue
+  bold: yes
+  underline: false
+text:
+  fg:
+    light: black
+    dark: 'white'
+`},
+		{"toml", `primary = "cyan"
+muted = 244 # 256-color gray
+selected = { fg = "black", bg = "#5fafd7", bold = true }
+
+[text.fg]
+light = "black"
+dark = 'white'
+`},
+	}
+	for _, tt := range tests {
+		th, err := theme.Parse([]byte(tt.data), tt.format)
+		if err != nil {
+			t.Errorf("Parse(%s) error = %v", tt.format, err)
+			continue
+		}
+		got := map[string]theme.Style{
+			"primary":  th.Primary,
+			"muted":    th.Muted,
+			"selected": th.Selected,
+			"text":     th.Text,
+		}
+		for token, style := range want {
+			if got[token] != style {
+				t.Errorf("Parse(%s): %s = %+v, want %+v", tt.format, token, got[token], style)
+			}
+		}
+		if th.Error != theme.Default.Error {
+			t.Errorf("Parse(%s): a token the file leaves out is not taken from Default", tt.format)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		format string
+		data   string
+		err    string
+	}{
+		{"ini", "", `theme: unknown format "ini"`},
+		{"json", `{"primary": `, "theme: unexpected end of JSON input"},
+		{"json", `{"primry": "red"}`, `theme: unknown token "primry"`},
+		{"json", `{"primary": "teal"}`, `theme: primary: unknown color "teal"`},
+		{"json", `{"primary": {"fg": "red", "blink": true}}`, "theme: primary.blink: unknown field"},
+		{"json", `{"primary": {"bold": "yes"}}`, "theme: primary.bold: expected true or false, got yes"},
+		{"json", `{"primary": {"fg": {"dim": "red"}}}`, "theme: primary.fg.dim: unknown field, expected light or dark"},
+		{"json", `{"primary": {"bg": {"dark": "#12"}}}`, `theme: primary.bg.dark: invalid hex color "#12"`},
+		{"yaml", "primary: #ff0000", `theme: line 1: hex colors must be quoted, as in primary: "#RRGGBB"`},
+		{"yaml", "primary:\n\tfg: red", "theme: line 2: tabs cannot be used for indentation"},
+		{"yaml", "primary:\nfg: red", "theme: line 2: expected an indented block"},
+		{"yaml", "primary:\n    fg: red\n  bg: blue", "theme: line 3: inconsistent indentation"},
+		{"yaml", "primary: red\nprimary: blue", `theme: line 2: duplicate key "primary"`},
+		{"yaml", "- primary", "theme: line 1: lists are not supported"},
+		{"yaml", "primary", `theme: line 1: expected "key: value"`},
+		{"yaml", "primary: {fg: red}", "theme: line 1: flow collections are not supported"},
+		{"yaml", `primary: "red`, `theme: line 1: unterminated string "red`},
+		{"toml", "[[themes]]", "theme: line 1: arrays of tables are not supported"},
+		{"toml", "[primary", "theme: line 1: unterminated table header"},
+		{"toml", "primary = red", `theme: line 1: invalid value "red", strings must be quoted`},
+		{"toml", "primary = \"red\"\nprimary = \"blue\"", `theme: line 2: duplicate key "primary"`},
+		{"toml", "[primary]\nfg = \"red\"\n[primary]", `theme: line 3: duplicate key "primary"`},
+		{"toml", `primary = "red" "blue"`, `theme: line 1: unexpected "\"blue\"" after value`},
+		{"toml", `primary = { fg = "red"`, "theme: line 1: unterminated inline table"},
+		{"toml", `primary = ["red"]`, "theme: line 1: arrays are not supported"},
+		{"toml", `primary = "red`, `theme: line 1: unterminated string "red`},
+		{"toml", "primary.fg. = 1", `theme: line 1: empty key in "primary.fg."`},
+	}
+	for _, tt := range tests {
+		_, err := theme.Parse([]byte(tt.data), tt.format)
+		if err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+			t.Errorf("Parse(%s, %q) error = %v, want %s", tt.format, tt.data, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package theme
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vynazevedo/termx/colorprofile"
+)
+
+// Color is a terminal color: one of the 16 ANSI colors, an index in the
+// 256-color palette or an RGB value. The zero value is the terminal's
+// default color.
+type Color uint32
+
+const (
+	colorANSI Color = 1 << (24 + iota)
+	colorIndexed
+	colorRGB
+
+	colorKind = colorANSI | colorIndexed | colorRGB
+)
+
+// The 16 ANSI colors. Terminals let users change their exact shades.
+const (
+	Black Color = colorANSI | iota
+	Red
+	Green
+	Yellow
+	Blue
+	Magenta
+	Cyan
+	White
+	BrightBlack
+	BrightRed
+	BrightGreen
+	BrightYellow
+	BrightBlue
+	BrightMagenta
+	BrightCyan
+	BrightWhite
+)
+
+// ANSI returns one of the 16 ANSI colors, 0 to 7 for the normal ones and 8
+// to 15 for the bright ones
+func ANSI(n int) Color {
+	return colorANSI | Color(n&0xf)
+}
+
+// Indexed returns a color of the xterm 256-color palette
+func Indexed(n int) Color {
+	return colorIndexed | Color(n&0xff)
+}
+
+// RGB returns a 24-bit color
+func RGB(r, g, b uint8) Color {
+	return colorRGB | Color(r)<<16 | Color(g)<<8 | Color(b)
+}
+
+// Hex returns the 24-bit color written as 0xRRGGBB
+func Hex(rgb uint32) Color {
+	return colorRGB | Color(rgb&0xffffff)
+}
+
+// IsSet reports whether c is a color rather than the terminal's default
+func (c Color) IsSet() bool {
+	return c&colorKind != 0
+}
+
+// String returns the color the way theme files write it
+func (c Color) String() string {
+	v := uint32(c & 0xffffff)
+	switch c & colorKind {
+	case colorANSI:
+		return ansiNames[v]
+	case colorIndexed:
+		return strconv.Itoa(int(v))
+	case colorRGB:
+		return fmt.Sprintf("#%06x", v)
+	}
+	return "default"
+}
+
+var ansiNames = [16]string{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"bright_black", "bright_red", "bright_green", "bright_yellow",
+	"bright_blue", "bright_magenta", "bright_cyan", "bright_white",
+}
+
+// params returns the SGR parameters that select c as the foreground or the
+// background color
+func (c Color) params(background bool) string {
+	v := uint32(c & 0xffffff)
+	layer := "38"
+	if background {
+		layer = "48"
+	}
+
+	switch c & colorKind {
+	case colorANSI:
+		code := 30 + v
+		if v >= 8 {
+			code = 90 + v - 8
+		}
+		if background {
+			code += 10
+		}
+		return strconv.Itoa(int(code))
+	case colorIndexed:
+		return layer + ";5;" + strconv.Itoa(int(v))
+	case colorRGB:
+		return fmt.Sprintf("%s;2;%d;%d;%d", layer, v>>16, v>>8&0xff, v&0xff)
+	}
+	return ""
+}
+
+// Attr is a set of text attributes
+type Attr uint8
+
+const (
+	AttrBold Attr = 1 << iota
+	AttrDim
+	AttrItalic
+	AttrUnderline
+	AttrStrikethrough
+	AttrReverse
+)
+
+// attrCodes lists the attributes with their SGR codes, in output order
+var attrCodes = []struct {
+	attr Attr
+	code string
+}{
+	{AttrBold, "1"},
+	{AttrDim, "2"},
+	{AttrItalic, "3"},
+	{AttrUnderline, "4"},
+	{AttrReverse, "7"},
+	{AttrStrikethrough, "9"},
+}
+
+// Style is how a piece of text looks: its colors and attributes. The zero
+// value leaves the text as the terminal shows it by default.
+//
+// LightFg and LightBg, when set, replace Fg and Bg on terminals with a light
+// background, so that one style reads well on both. See DarkBackground.
+type Style struct {
+	Fg    Color
+	Bg    Color
+	Attrs Attr
+
+	LightFg Color
+	LightBg Color
+}
+
+// Foreground returns s with the text color set to c
+func (s Style) Foreground(c Color) Style {
+	s.Fg = c
+	return s
+}
+
+// Background returns s with the background color set to c
+func (s Style) Background(c Color) Style {
+	s.Bg = c
+	return s
+}
+
+// With returns s with the attributes a added
+func (s Style) With(a Attr) Style {
+	s.Attrs |= a
+	return s
+}
+
+func (s Style) Bold() Style          { return s.With(AttrBold) }
+func (s Style) Dim() Style           { return s.With(AttrDim) }
+func (s Style) Italic() Style        { return s.With(AttrItalic) }
+func (s Style) Underline() Style     { return s.With(AttrUnderline) }
+func (s Style) Strikethrough() Style { return s.With(AttrStrikethrough) }
+func (s Style) Reverse() Style       { return s.With(AttrReverse) }
+
+// Has reports whether s has all the attributes a
+func (s Style) Has(a Attr) bool {
+	return s.Attrs&a == a
+}
+
+// IsZero reports whether s changes nothing about the text
+func (s Style) IsZero() bool {
+	return s == Style{}
+}
+
+// Inherit returns s with the colors it leaves unset taken from parent and
+// the attributes of both. A color and its light variant are taken together.
+func (s Style) Inherit(parent Style) Style {
+	if !s.Fg.IsSet() && !s.LightFg.IsSet() {
+		s.Fg, s.LightFg = parent.Fg, parent.LightFg
+	}
+	if !s.Bg.IsSet() && !s.LightBg.IsSet() {
+		s.Bg, s.LightBg = parent.Bg, parent.LightBg
+	}
+	s.Attrs |= parent.Attrs
+	return s
+}
+
+// Adapt returns s with the colors for the given background: the light
+// variants replace Fg and Bg when dark is false
+func (s Style) Adapt(dark bool) Style {
+	if !dark {
+		if s.LightFg.IsSet() {
+			s.Fg = s.LightFg
+		}
+		if s.LightBg.IsSet() {
+			s.Bg = s.LightBg
+		}
+	}
+	s.LightFg, s.LightBg = 0, 0
+	return s
+}
+
+// Merge returns s with the colors other sets replaced and the attributes of
+// both, the way other would be drawn over s
+func (s Style) Merge(other Style) Style {
+	return other.Inherit(s)
+}
+
+// Sequence returns the SGR escape sequence that turns the style on, or ""
+// for the zero style. Colors are chosen for DarkBackground() and written as
+// they were given; the renderer converts them to the color profile of its
+// output.
+func (s Style) Sequence() string {
+	s = s.Adapt(DarkBackground())
+	var params []string
+	for _, a := range attrCodes {
+		if s.Attrs&a.attr != 0 {
+			params = append(params, a.code)
+		}
+	}
+	if s.Fg.IsSet() {
+		params = append(params, s.Fg.params(false))
+	}
+	if s.Bg.IsSet() {
+		params = append(params, s.Bg.params(true))
+	}
+	if len(params) == 0 {
+		return ""
+	}
+	return "\033[" + strings.Join(params, ";") + "m"
+}
+
+// Sprint returns text in the style, followed by a reset
+func (s Style) Sprint(text string) string {
+	seq := s.Sequence()
+	if seq == "" {
+		return text
+	}
+	return seq + text + "\033[0m"
+}
+
+func (s Style) Sprintf(format string, args ...interface{}) string {
+	return s.Sprint(fmt.Sprintf(format, args...))
+}
+
+// Render is like Sprint but maps the colors to the nearest ones the profile
+// supports, for text written without a renderer
+func (s Style) Render(p colorprofile.Profile, text string) string {
+	seq := p.Convert(s.Sequence())
+	if seq == "" {
+		return text
+	}
+	return seq + text + "\033[0m"
+}
+
+// Highlight returns text in the base style with the first case-insensitive
+// occurrence of query drawn in match inherited from base
+func Highlight(text, query string, base, match Style) string {
+	lower, q := strings.ToLower(text), strings.ToLower(query)
+	start := strings.Index(lower, q)
+	// Lowercasing changes the length of a few characters, which would put
+	// the match at the wrong offset
+	if q == "" || start < 0 || len(lower) != len(text) || len(q) != len(query) {
+		return base.Sprint(text)
+	}
+
+	end := start + len(query)
+	out := match.Inherit(base).Sprint(text[start:end])
+	if start > 0 {
+		out = base.Sprint(text[:start]) + out
+	}
+	if end < len(text) {
+		out += base.Sprint(text[end:])
+	}
+	return out
+}
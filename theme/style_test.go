@@ -0,0 +1,93 @@
+package theme_test
+
+import (
+	"testing"
+
+	"github.com/vynazevedo/termx/colorprofile"
+	"github.com/vynazevedo/termx/theme"
+)
+
+func TestStyleSequence(t *testing.T) {
+	theme.SetDarkBackground(true)
+
+	tests := []struct {
+		style theme.Style
+		want  string
+	}{
+		{theme.Style{}, ""},
+		{theme.Style{Fg: theme.Red}, "\033[31m"},
+		{theme.Style{Fg: theme.BrightCyan, Bg: theme.Blue}, "\033[96;44m"},
+		{theme.Style{Bg: theme.BrightWhite}, "\033[107m"},
+		{theme.Style{Fg: theme.Indexed(202)}, "\033[38;5;202m"},
+		{theme.Style{Fg: theme.Hex(0x5fafd7), Bg: theme.RGB(1, 2, 3)}, "\033[38;2;95;175;215;48;2;1;2;3m"},
+		{theme.Style{}.Bold().Underline().Strikethrough(), "\033[1;4;9m"},
+		{theme.Style{Fg: theme.Green}.Reverse().Dim(), "\033[2;7;32m"},
+	}
+	for _, tt := range tests {
+		if got := tt.style.Sequence(); got != tt.want {
+			t.Errorf("%+v.Sequence() = %q, want %q", tt.style, got, tt.want)
+		}
+	}
+}
+
+func TestStyleRender(t *testing.T) {
+	theme.SetDarkBackground(true)
+
+	s := theme.Style{Fg: theme.Hex(0xff0000)}.Bold()
+	tests := []struct {
+		profile colorprofile.Profile
+		want    string
+	}{
+		{colorprofile.TrueColor, "\033[1;38;2;255;0;0mx\033[0m"},
+		{colorprofile.ANSI256, "\033[1;38;5;196mx\033[0m"},
+		{colorprofile.ANSI, "\033[1;91mx\033[0m"},
+		{colorprofile.Ascii, "x"},
+	}
+	for _, tt := range tests {
+		if got := s.Render(tt.profile, "x"); got != tt.want {
+			t.Errorf("Render(%v) = %q, want %q", tt.profile, got, tt.want)
+		}
+	}
+}
+
+func TestStyleInherit(t *testing.T) {
+	parent := theme.Style{Fg: theme.Red, Bg: theme.Blue, LightBg: theme.White}.Bold()
+	tests := []struct {
+		child theme.Style
+		want  theme.Style
+	}{
+		{theme.Style{}, parent},
+		{theme.Style{Fg: theme.Green}, theme.Style{Fg: theme.Green, Bg: theme.Blue, LightBg: theme.White, Attrs: theme.AttrBold}},
+		{theme.Style{LightBg: theme.Black}.Italic(), theme.Style{Fg: theme.Red, LightBg: theme.Black, Attrs: theme.AttrBold | theme.AttrItalic}},
+	}
+	for _, tt := range tests {
+		if got := tt.child.Inherit(parent); got != tt.want {
+			t.Errorf("%+v.Inherit(parent) = %+v, want %+v", tt.child, got, tt.want)
+		}
+	}
+	if got := parent.Merge(theme.Style{Fg: theme.Green}); got.Fg != theme.Green || got.Bg != theme.Blue {
+		t.Errorf("Merge() = %+v, want green on blue", got)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	theme.SetDarkBackground(true)
+
+	base := theme.Style{Fg: theme.White}
+	match := theme.Style{}.Bold()
+	tests := []struct {
+		text, query, want string
+	}{
+		{"banana", "", "\033[37mbanana\033[0m"},
+		{"banana", "kiwi", "\033[37mbanana\033[0m"},
+		{"banana", "AN", "\033[37mb\033[0m\033[1;37man\033[0m\033[37mana\033[0m"},
+		{"banana", "ban", "\033[1;37mban\033[0m\033[37mana\033[0m"},
+		{"banana", "na", "\033[37mba\033[0m\033[1;37mna\033[0m\033[37mna\033[0m"},
+		{"İstanbul", "stan", "\033[37mİstanbul\033[0m"},
+	}
+	for _, tt := range tests {
+		if got := theme.Highlight(tt.text, tt.query, base, match); got != tt.want {
+			t.Errorf("Highlight(%q, %q) = %q, want %q", tt.text, tt.query, got, tt.want)
+		}
+	}
+}
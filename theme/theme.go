@@ -1,64 +1,68 @@
 package theme
 
-import "fmt"
-
 type Theme struct {
-	Primary      Color
-	Secondary    Color
-	Success      Color
-	Error        Color
-	Warning      Color
-	Info         Color
-	Text         Color
-	TextDim      Color
-	Background   Color
-	Border       Color
-	Cursor       Color
-	Selected     Color
-	Placeholder  Color
-	Muted        Color
-}
+	Primary      Style
+	Secondary    Style
+	Success      Style
+	Error        Style
+	Warning      Style
+	Info         Style
+	Text         Style
+	TextDim      Style
+	Background   Style
+	Border       Style
+	Focus        Style // border of the widget that has the keyboard focus
+	Cursor       Style
+	Selected     Style
+	Placeholder  Style
+	Muted        Style
 
-type Color struct {
-	Foreground string
-	Background string
+	// Component tokens
+	Disabled     Style // items that cannot be chosen
+	Match        Style // part of an item that matches the filter, drawn over the item style
+	HelpKey      Style // keys in help bars and the help overlay
+	HelpText     Style // descriptions in help bars and the help overlay
 }
 
 var Default = &Theme{
-	Primary:     Color{Foreground: "\033[36m"},       // Cyan
-	Secondary:   Color{Foreground: "\033[35m"},       // Magenta
-	Success:     Color{Foreground: "\033[32m"},       // Green
-	Error:       Color{Foreground: "\033[31m"},       // Red
-	Warning:     Color{Foreground: "\033[33m"},       // Yellow
-	Info:        Color{Foreground: "\033[34m"},       // Blue
-	Text:        Color{Foreground: "\033[37m"},       // White
-	TextDim:     Color{Foreground: "\033[90m"},       // Bright Black (Gray)
-	Background:  Color{Background: "\033[40m"},       // Black
-	Border:      Color{Foreground: "\033[90m"},       // Gray
-	Cursor:      Color{Foreground: "\033[36m"},       // Cyan
-	Selected:    Color{Foreground: "\033[30m", Background: "\033[46m"}, // Black on Cyan
-	Placeholder: Color{Foreground: "\033[90m"},       // Gray
-	Muted:       Color{Foreground: "\033[90m"},       // Gray
+	Primary:     Style{Fg: Cyan},
+	Secondary:   Style{Fg: Magenta},
+	Success:     Style{Fg: Green},
+	Error:       Style{Fg: Red},
+	Warning:     Style{Fg: Yellow},
+	Info:        Style{Fg: Blue},
+	Text:        Style{Fg: White, LightFg: Black},
+	TextDim:     Style{Fg: BrightBlack},
+	Background:  Style{Bg: Black, LightBg: White},
+	Border:      Style{Fg: BrightBlack},
+	Focus:       Style{Fg: Cyan},
+	Cursor:      Style{Fg: Cyan},
+	Selected:    Style{Fg: Black, Bg: Cyan},
+	Placeholder: Style{Fg: BrightBlack},
+	Muted:       Style{Fg: BrightBlack},
+	Disabled:    Style{Fg: BrightBlack},
+	Match:       Style{Attrs: AttrBold | AttrUnderline},
+	HelpKey:     Style{Fg: Cyan},
+	HelpText:    Style{Fg: BrightBlack},
 }
 
 var current = Default
 
+// Current returns the theme components are drawn with: the one named by
+// TERMX_THEME, loaded on the first call, or else the one given to Set
 func Current() *Theme {
+	if t, _ := loadEnv(true); t != nil {
+		return t
+	}
 	return current
 }
 
+// Set makes theme the current one, unless the user chose another with
+// TERMX_THEME
 func Set(theme *Theme) {
 	current = theme
 }
 
-func (c Color) Sprint(text string) string {
-	return c.Foreground + c.Background + text + "\033[0m"
-}
-
-func (c Color) Sprintf(format string, args ...interface{}) string {
-	return c.Sprint(fmt.Sprintf(format, args...))
-}
-
 func Bold(text string) string {
 	return "\033[1m" + text + "\033[22m"
 }
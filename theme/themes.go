@@ -0,0 +1,201 @@
+package theme
+
+import (
+	"sort"
+	"strings"
+)
+
+// Dracula is the dark Dracula palette
+var Dracula = &Theme{
+	Primary:     Style{Fg: Hex(0xbd93f9)}, // Purple
+	Secondary:   Style{Fg: Hex(0xff79c6)}, // Pink
+	Success:     Style{Fg: Hex(0x50fa7b)}, // Green
+	Error:       Style{Fg: Hex(0xff5555)}, // Red
+	Warning:     Style{Fg: Hex(0xffb86c)}, // Orange
+	Info:        Style{Fg: Hex(0x8be9fd)}, // Cyan
+	Text:        Style{Fg: Hex(0xf8f8f2)}, // Foreground
+	TextDim:     Style{Fg: Hex(0x6272a4)}, // Comment
+	Background:  Style{Bg: Hex(0x282a36)},
+	Border:      Style{Fg: Hex(0x6272a4)},
+	Focus:       Style{Fg: Hex(0xbd93f9)},
+	Cursor:      Style{Fg: Hex(0xff79c6)},
+	Selected:    Style{Fg: Hex(0xf8f8f2), Bg: Hex(0x44475a)}, // Current line
+	Placeholder: Style{Fg: Hex(0x6272a4)},
+	Muted:       Style{Fg: Hex(0x6272a4)},
+	Disabled:    Style{Fg: Hex(0x6272a4), Attrs: AttrStrikethrough},
+	Match:       Style{Fg: Hex(0xf1fa8c), Attrs: AttrBold}, // Yellow
+	HelpKey:     Style{Fg: Hex(0x8be9fd)},
+	HelpText:    Style{Fg: Hex(0x6272a4)},
+}
+
+// Nord is the dark Nord palette
+var Nord = &Theme{
+	Primary:     Style{Fg: Hex(0x88c0d0)}, // Frost
+	Secondary:   Style{Fg: Hex(0xb48ead)}, // Purple
+	Success:     Style{Fg: Hex(0xa3be8c)}, // Green
+	Error:       Style{Fg: Hex(0xbf616a)}, // Red
+	Warning:     Style{Fg: Hex(0xebcb8b)}, // Yellow
+	Info:        Style{Fg: Hex(0x81a1c1)}, // Frost
+	Text:        Style{Fg: Hex(0xeceff4)}, // Snow Storm
+	TextDim:     Style{Fg: Hex(0xd8dee9)},
+	Background:  Style{Bg: Hex(0x2e3440)}, // Polar Night
+	Border:      Style{Fg: Hex(0x4c566a)},
+	Focus:       Style{Fg: Hex(0x88c0d0)},
+	Cursor:      Style{Fg: Hex(0x88c0d0)},
+	Selected:    Style{Fg: Hex(0x2e3440), Bg: Hex(0x88c0d0)},
+	Placeholder: Style{Fg: Hex(0x4c566a)},
+	Muted:       Style{Fg: Hex(0x4c566a)},
+	Disabled:    Style{Fg: Hex(0x4c566a)},
+	Match:       Style{Fg: Hex(0xebcb8b), Attrs: AttrBold},
+	HelpKey:     Style{Fg: Hex(0x81a1c1)},
+	HelpText:    Style{Fg: Hex(0x4c566a)},
+}
+
+// SolarizedDark is Solarized for dark backgrounds
+var SolarizedDark = &Theme{
+	Primary:     Style{Fg: Hex(0x268bd2)}, // Blue
+	Secondary:   Style{Fg: Hex(0x6c71c4)}, // Violet
+	Success:     Style{Fg: Hex(0x859900)}, // Green
+	Error:       Style{Fg: Hex(0xdc322f)}, // Red
+	Warning:     Style{Fg: Hex(0xb58900)}, // Yellow
+	Info:        Style{Fg: Hex(0x2aa198)}, // Cyan
+	Text:        Style{Fg: Hex(0x839496)}, // base0
+	TextDim:     Style{Fg: Hex(0x586e75)}, // base01
+	Background:  Style{Bg: Hex(0x002b36)}, // base03
+	Border:      Style{Fg: Hex(0x586e75)},
+	Focus:       Style{Fg: Hex(0x268bd2)},
+	Cursor:      Style{Fg: Hex(0xcb4b16)},                    // Orange
+	Selected:    Style{Fg: Hex(0x93a1a1), Bg: Hex(0x073642)}, // base1 on base02
+	Placeholder: Style{Fg: Hex(0x586e75)},
+	Muted:       Style{Fg: Hex(0x586e75)},
+	Disabled:    Style{Fg: Hex(0x586e75)},
+	Match:       Style{Fg: Hex(0xb58900), Attrs: AttrBold},
+	HelpKey:     Style{Fg: Hex(0x268bd2)},
+	HelpText:    Style{Fg: Hex(0x586e75)},
+}
+
+// SolarizedLight is Solarized for light backgrounds
+var SolarizedLight = &Theme{
+	Primary:     Style{Fg: Hex(0x268bd2)}, // Blue
+	Secondary:   Style{Fg: Hex(0x6c71c4)}, // Violet
+	Success:     Style{Fg: Hex(0x859900)}, // Green
+	Error:       Style{Fg: Hex(0xdc322f)}, // Red
+	Warning:     Style{Fg: Hex(0xb58900)}, // Yellow
+	Info:        Style{Fg: Hex(0x2aa198)}, // Cyan
+	Text:        Style{Fg: Hex(0x657b83)}, // base00
+	TextDim:     Style{Fg: Hex(0x93a1a1)}, // base1
+	Background:  Style{Bg: Hex(0xfdf6e3)}, // base3
+	Border:      Style{Fg: Hex(0x93a1a1)},
+	Focus:       Style{Fg: Hex(0x268bd2)},
+	Cursor:      Style{Fg: Hex(0xcb4b16)},                    // Orange
+	Selected:    Style{Fg: Hex(0x586e75), Bg: Hex(0xeee8d5)}, // base01 on base2
+	Placeholder: Style{Fg: Hex(0x93a1a1)},
+	Muted:       Style{Fg: Hex(0x93a1a1)},
+	Disabled:    Style{Fg: Hex(0x93a1a1)},
+	Match:       Style{Fg: Hex(0xb58900), Attrs: AttrBold},
+	HelpKey:     Style{Fg: Hex(0x268bd2)},
+	HelpText:    Style{Fg: Hex(0x93a1a1)},
+}
+
+// HighContrast uses bold bright colors and avoids dim text, for low vision
+// and bright screens. It sticks to the 16 basic colors so that terminals
+// with custom palettes keep their contrast settings.
+var HighContrast = &Theme{
+	Primary:     Style{Fg: BrightYellow, Attrs: AttrBold},
+	Secondary:   Style{Fg: BrightCyan, Attrs: AttrBold},
+	Success:     Style{Fg: BrightGreen, Attrs: AttrBold},
+	Error:       Style{Fg: BrightRed, Attrs: AttrBold},
+	Warning:     Style{Fg: BrightYellow, Attrs: AttrBold},
+	Info:        Style{Fg: BrightCyan, Attrs: AttrBold},
+	Text:        Style{Fg: BrightWhite},
+	TextDim:     Style{Fg: BrightWhite},
+	Background:  Style{Bg: Black},
+	Border:      Style{Fg: BrightWhite},
+	Focus:       Style{Fg: BrightYellow, Attrs: AttrBold},
+	Cursor:      Style{Fg: BrightYellow, Attrs: AttrBold},
+	Selected:    Style{Fg: Black, Bg: BrightYellow, Attrs: AttrBold},
+	Placeholder: Style{Fg: BrightWhite, Attrs: AttrItalic},
+	Muted:       Style{Fg: BrightWhite},
+	Disabled:    Style{Fg: BrightWhite, Attrs: AttrStrikethrough},
+	Match:       Style{Attrs: AttrBold | AttrUnderline},
+	HelpKey:     Style{Fg: BrightYellow, Attrs: AttrBold},
+	HelpText:    Style{Fg: BrightWhite},
+}
+
+// Monochrome sets no colors at all, only bold, underline and reverse, for
+// terminals without color support and for NO_COLOR users
+var Monochrome = &Theme{
+	Primary:     Style{Attrs: AttrBold},
+	Secondary:   Style{Attrs: AttrUnderline},
+	Success:     Style{Attrs: AttrBold},
+	Error:       Style{Attrs: AttrBold | AttrUnderline},
+	Warning:     Style{Attrs: AttrBold},
+	Focus:       Style{Attrs: AttrBold},
+	Cursor:      Style{Attrs: AttrBold},
+	Selected:    Style{Attrs: AttrReverse},
+	Placeholder: Style{Attrs: AttrUnderline},
+	Disabled:    Style{Attrs: AttrUnderline},
+	Match:       Style{Attrs: AttrBold | AttrUnderline},
+	HelpKey:     Style{Attrs: AttrBold},
+}
+
+var registry = map[string]*Theme{
+	"default":         Default,
+	"dracula":         Dracula,
+	"nord":            Nord,
+	"solarized-dark":  SolarizedDark,
+	"solarized-light": SolarizedLight,
+	"high-contrast":   HighContrast,
+	"monochrome":      Monochrome,
+}
+
+// adaptive holds the themes registered with a light and a dark variant
+var adaptive = map[string][2]*Theme{
+	"solarized": {SolarizedLight, SolarizedDark},
+}
+
+// Register adds t to the themes that can be looked up by name, replacing any
+// theme registered under the same name. Names are case-insensitive.
+func Register(name string, t *Theme) {
+	if name == "" || t == nil {
+		panic("theme: Register needs a name and a theme")
+	}
+	name = strings.ToLower(name)
+	delete(adaptive, name)
+	registry[name] = t
+}
+
+// RegisterAdaptive is like Register for a theme with a variant for light
+// and one for dark backgrounds. Lookup returns the one that matches
+// DarkBackground().
+func RegisterAdaptive(name string, light, dark *Theme) {
+	if name == "" || light == nil || dark == nil {
+		panic("theme: RegisterAdaptive needs a name and both variants")
+	}
+	name = strings.ToLower(name)
+	delete(registry, name)
+	adaptive[name] = [2]*Theme{light, dark}
+}
+
+// Lookup returns the theme registered under name
+func Lookup(name string) (*Theme, bool) {
+	name = strings.ToLower(name)
+	if variants, ok := adaptive[name]; ok {
+		if DarkBackground() {
+			return variants[1], true
+		}
+		return variants[0], true
+	}
+	t, ok := registry[name]
+	return t, ok
+}
+
+// Names returns the names of the registered themes, sorted
+func Names() []string {
+	names := sortedKeys(registry)
+	for name := range adaptive {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,64 @@
+package theme_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/vynazevedo/termx/theme"
+)
+
+func TestRegistry(t *testing.T) {
+	defer theme.SetDarkBackground(true)
+
+	custom := &theme.Theme{}
+	theme.Register("Company", custom)
+	if got, ok := theme.Lookup("COMPANY"); !ok || got != custom {
+		t.Error("Lookup does not find a theme registered under another case")
+	}
+	if !slices.Contains(theme.Names(), "company") || !slices.IsSorted(theme.Names()) {
+		t.Errorf("Names() = %v, want the sorted names including company", theme.Names())
+	}
+
+	light, dark := &theme.Theme{}, &theme.Theme{}
+	theme.RegisterAdaptive("company", light, dark)
+	for _, isDark := range []bool{true, false} {
+		theme.SetDarkBackground(isDark)
+		want := light
+		if isDark {
+			want = dark
+		}
+		if got, _ := theme.Lookup("company"); got != want {
+			t.Errorf("dark=%v: Lookup returned the wrong variant of an adaptive theme", isDark)
+		}
+	}
+	if _, ok := theme.Lookup("nonexistent"); ok {
+		t.Error("Lookup found a theme that was never registered")
+	}
+}
+
+func TestFromEnvNames(t *testing.T) {
+	tests := []struct {
+		value string
+		want  *theme.Theme
+		err   string
+	}{
+		{"", nil, ""},
+		{"Nord", theme.Nord, ""},
+		{"default", theme.Default, ""},
+		{"nrod", nil, `TERMX_THEME: unknown theme "nrod"`},
+		{"missing.toml", nil, "TERMX_THEME: open missing.toml"},
+	}
+	for _, tt := range tests {
+		t.Setenv(theme.EnvVar, tt.value)
+		got, err := theme.FromEnv()
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("TERMX_THEME=%s: FromEnv() error = %v", tt.value, err)
+		case tt.err != "" && (err == nil || !strings.HasPrefix(err.Error(), tt.err)):
+			t.Errorf("TERMX_THEME=%s: FromEnv() error = %v, want %s...", tt.value, err, tt.err)
+		case got != tt.want:
+			t.Errorf("TERMX_THEME=%s: FromEnv() returned the wrong theme", tt.value)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package theme
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseTOML parses the subset of TOML used by theme files: tables, dotted
+// keys, inline tables, strings, integers, booleans and # comments
+func parseTOML(src string) (map[string]any, error) {
+	root := map[string]any{}
+	table := root
+
+	for i, raw := range strings.Split(src, "\n") {
+		n := i + 1
+		line := strings.TrimSpace(stripComment(strings.TrimRight(raw, "\r")))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if strings.HasPrefix(line, "[[") {
+				return nil, fmt.Errorf("theme: line %d: arrays of tables are not supported", n)
+			}
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("theme: line %d: unterminated table header", n)
+			}
+			keys, err := tomlKey(line[1 : len(line)-1])
+			if err != nil {
+				return nil, fmt.Errorf("theme: line %d: %w", n, err)
+			}
+			table, err = tomlTable(root, keys, true)
+			if err != nil {
+				return nil, fmt.Errorf("theme: line %d: %w", n, err)
+			}
+			continue
+		}
+
+		if err := tomlAssign(table, line); err != nil {
+			return nil, fmt.Errorf("theme: line %d: %w", n, err)
+		}
+	}
+	return root, nil
+}
+
+// tomlAssign parses "key = value" into table
+func tomlAssign(table map[string]any, s string) error {
+	eq := strings.Index(s, "=")
+	if eq <= 0 {
+		return fmt.Errorf("expected \"key = value\"")
+	}
+	keys, err := tomlKey(s[:eq])
+	if err != nil {
+		return err
+	}
+	value, rest, err := tomlValue(strings.TrimSpace(s[eq+1:]))
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(rest) != "" {
+		return fmt.Errorf("unexpected %q after value", strings.TrimSpace(rest))
+	}
+
+	parent, err := tomlTable(table, keys[:len(keys)-1], false)
+	if err != nil {
+		return err
+	}
+	last := keys[len(keys)-1]
+	if _, ok := parent[last]; ok {
+		return fmt.Errorf("duplicate key %q", strings.Join(keys, "."))
+	}
+	parent[last] = value
+	return nil
+}
+
+// tomlTable returns the table at keys below root, creating missing ones.
+// A header must not name a table that was already defined.
+func tomlTable(root map[string]any, keys []string, header bool) (map[string]any, error) {
+	t := root
+	for i, key := range keys {
+		v, ok := t[key]
+		if !ok {
+			next := map[string]any{}
+			t[key] = next
+			t = next
+			continue
+		}
+		next, isTable := v.(map[string]any)
+		if !isTable || (header && i == len(keys)-1) {
+			return nil, fmt.Errorf("duplicate key %q", strings.Join(keys[:i+1], "."))
+		}
+		t = next
+	}
+	return t, nil
+}
+
+// tomlKey splits a possibly dotted key
+func tomlKey(s string) ([]string, error) {
+	var keys []string
+	for _, part := range strings.Split(s, ".") {
+		part = strings.TrimSpace(part)
+		if len(part) >= 2 && (part[0] == '"' || part[0] == '\'') && part[len(part)-1] == part[0] {
+			part = part[1 : len(part)-1]
+		}
+		if part == "" {
+			return nil, fmt.Errorf("empty key in %q", strings.TrimSpace(s))
+		}
+		keys = append(keys, part)
+	}
+	return keys, nil
+}
+
+// tomlValue parses the value at the start of s and returns what follows it
+func tomlValue(s string) (any, string, error) {
+	if s == "" {
+		return nil, "", fmt.Errorf("missing value")
+	}
+
+	switch s[0] {
+	case '"':
+		end := 1
+		for end < len(s) && s[end] != '"' {
+			if s[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(s) {
+			return nil, "", fmt.Errorf("unterminated string %s", s)
+		}
+		v, err := strconv.Unquote(s[:end+1])
+		return v, s[end+1:], err
+	case '\'':
+		end := strings.IndexByte(s[1:], '\'')
+		if end < 0 {
+			return nil, "", fmt.Errorf("unterminated string %s", s)
+		}
+		return s[1 : end+1], s[end+2:], nil
+	case '{':
+		return tomlInline(s[1:])
+	case '[':
+		return nil, "", fmt.Errorf("arrays are not supported")
+	}
+
+	end := strings.IndexAny(s, ",}")
+	if end < 0 {
+		end = len(s)
+	}
+	word := strings.TrimSpace(s[:end])
+	switch word {
+	case "true":
+		return true, s[end:], nil
+	case "false":
+		return false, s[end:], nil
+	}
+	n, err := strconv.Atoi(strings.ReplaceAll(word, "_", ""))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid value %q, strings must be quoted", word)
+	}
+	return n, s[end:], nil
+}
+
+// tomlInline parses the rest of an inline table after the opening brace
+func tomlInline(s string) (any, string, error) {
+	t := map[string]any{}
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "}") {
+		return t, s[1:], nil
+	}
+
+	for {
+		eq := strings.Index(s, "=")
+		if eq <= 0 {
+			return nil, "", fmt.Errorf("expected \"key = value\" in inline table")
+		}
+		keys, err := tomlKey(s[:eq])
+		if err != nil {
+			return nil, "", err
+		}
+		value, rest, err := tomlValue(strings.TrimSpace(s[eq+1:]))
+		if err != nil {
+			return nil, "", err
+		}
+		parent, err := tomlTable(t, keys[:len(keys)-1], false)
+		if err != nil {
+			return nil, "", err
+		}
+		last := keys[len(keys)-1]
+		if _, ok := parent[last]; ok {
+			return nil, "", fmt.Errorf("duplicate key %q", strings.Join(keys, "."))
+		}
+		parent[last] = value
+
+		rest = strings.TrimSpace(rest)
+		switch {
+		case strings.HasPrefix(rest, ","):
+			s = strings.TrimSpace(rest[1:])
+		case strings.HasPrefix(rest, "}"):
+			return t, rest[1:], nil
+		default:
+			return nil, "", fmt.Errorf("unterminated inline table")
+		}
+	}
+}
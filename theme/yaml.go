@@ -0,0 +1,157 @@
+package theme
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseYAML parses the subset of YAML used by theme files: nested maps of
+// scalars, indented with spaces, and # comments
+func parseYAML(src string) (map[string]any, error) {
+	type frame struct {
+		indent int
+		m      map[string]any
+	}
+
+	root := map[string]any{}
+	stack := []frame{{indent: 0, m: root}}
+	// pending is a key whose value is the map on the following lines
+	var pending map[string]any
+	pendingIndent := -1
+
+	for i, raw := range strings.Split(src, "\n") {
+		n := i + 1
+		line := strings.TrimRight(stripComment(raw), " \r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if line == "---" {
+			continue
+		}
+
+		trimmed := strings.TrimLeft(line, " ")
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("theme: line %d: tabs cannot be used for indentation", n)
+		}
+		indent := len(line) - len(trimmed)
+
+		if pending != nil {
+			if indent <= pendingIndent {
+				return nil, fmt.Errorf("theme: line %d: expected an indented block", n)
+			}
+			stack = append(stack, frame{indent: indent, m: pending})
+			pending = nil
+		}
+		for indent < stack[len(stack)-1].indent {
+			stack = stack[:len(stack)-1]
+		}
+		top := stack[len(stack)-1]
+		if indent != top.indent {
+			return nil, fmt.Errorf("theme: line %d: inconsistent indentation", n)
+		}
+
+		if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+			return nil, fmt.Errorf("theme: line %d: lists are not supported", n)
+		}
+		colon := strings.Index(trimmed, ":")
+		if colon <= 0 || (colon+1 < len(trimmed) && trimmed[colon+1] != ' ') {
+			return nil, fmt.Errorf("theme: line %d: expected \"key: value\"", n)
+		}
+		key, err := unquoteKey(strings.TrimSpace(trimmed[:colon]))
+		if err != nil {
+			return nil, fmt.Errorf("theme: line %d: %w", n, err)
+		}
+		if _, ok := top.m[key]; ok {
+			return nil, fmt.Errorf("theme: line %d: duplicate key %q", n, key)
+		}
+
+		value := strings.TrimSpace(trimmed[colon+1:])
+		if value == "" && hexComment(raw[strings.Index(raw, ":")+1:]) {
+			return nil, fmt.Errorf("theme: line %d: hex colors must be quoted, as in %s: \"#RRGGBB\"", n, key)
+		}
+		if value == "" {
+			pending = map[string]any{}
+			pendingIndent = indent
+			top.m[key] = pending
+			continue
+		}
+		v, err := yamlScalar(value)
+		if err != nil {
+			return nil, fmt.Errorf("theme: line %d: %w", n, err)
+		}
+		top.m[key] = v
+	}
+	return root, nil
+}
+
+// stripComment removes a # comment that is not inside quotes
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// hexComment reports whether s, the text after a key, is an unquoted hex
+// color that YAML takes for a comment
+func hexComment(s string) bool {
+	word := strings.Fields(s + " ")
+	if len(word) == 0 || len(word[0]) != 7 || word[0][0] != '#' {
+		return false
+	}
+	_, err := strconv.ParseUint(word[0][1:], 16, 32)
+	return err == nil
+}
+
+func unquoteKey(key string) (string, error) {
+	if len(key) > 0 && (key[0] == '"' || key[0] == '\'') {
+		v, err := yamlScalar(key)
+		if err != nil {
+			return "", err
+		}
+		return v.(string), nil
+	}
+	return key, nil
+}
+
+// yamlScalar converts a scalar to a string, int or bool
+func yamlScalar(s string) (any, error) {
+	switch {
+	case s[0] == '"':
+		if len(s) < 2 || s[len(s)-1] != '"' {
+			return nil, fmt.Errorf("unterminated string %s", s)
+		}
+		return strconv.Unquote(s)
+	case s[0] == '\'':
+		if len(s) < 2 || s[len(s)-1] != '\'' {
+			return nil, fmt.Errorf("unterminated string %s", s)
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	case s[0] == '{' || s[0] == '[':
+		return nil, fmt.Errorf("flow collections are not supported, use an indented block")
+	}
+
+	switch strings.ToLower(s) {
+	case "true", "yes", "on":
+		return true, nil
+	case "false", "no", "off":
+		return false, nil
+	case "~", "null":
+		return nil, nil
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, nil
+	}
+	return s, nil
+}
@@ -0,0 +1,67 @@
+package widget
+
+import (
+	"context"
+
+	"github.com/vynazevedo/termx/renderer"
+	"github.com/vynazevedo/termx/theme"
+)
+
+// Run initializes r and runs w full screen until it is done, returning the
+// error it finished with. The screen is redrawn after every handled event
+// and whenever the terminal is resized. Ctrl+C that w ignores ends the run
+// with ErrCancelled; once ctx is done Run returns ctx.Err().
+func Run(ctx context.Context, r *renderer.Renderer, w Widget) error {
+	return run(ctx, r, w, false, func() {
+		r.Clear()
+		c := r.Canvas()
+		w.Draw(c, c.Bounds())
+		r.Flush()
+	})
+}
+
+// RunInline is like Run but draws w below the previous output. The lines
+// are cleared when it returns, so the caller can leave a summary behind.
+func RunInline(ctx context.Context, r *renderer.Renderer, w InlineWidget) error {
+	return run(ctx, r, w, true, func() {
+		w.DrawInline(r)
+	})
+}
+
+func run(ctx context.Context, r *renderer.Renderer, w Widget, inline bool, draw func()) error {
+	// Load the theme chosen with TERMX_THEME while an error loading it can
+	// still be read on the terminal
+	theme.Current()
+	if err := r.Init(); err != nil {
+		return err
+	}
+	defer r.Restore()
+	defer renderer.RestoreOnPanic()
+	if inline {
+		defer r.ClearInline()
+	}
+
+	for redraw := true; ; {
+		if redraw {
+			draw()
+		}
+
+		event, err := r.ReadInputContext(ctx)
+		if err != nil {
+			return err
+		}
+		if event.Key == renderer.KeyResize {
+			redraw = true
+			continue
+		}
+
+		res := w.HandleEvent(event)
+		switch {
+		case res.IsDone():
+			return res.Err()
+		case !res.IsHandled() && event.Key == renderer.KeyCtrlC:
+			return ErrCancelled
+		}
+		redraw = res.IsHandled()
+	}
+}
@@ -0,0 +1,151 @@
+package widget_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/vynazevedo/termx/renderer"
+	"github.com/vynazevedo/termx/termxtest"
+	"github.com/vynazevedo/termx/widget"
+)
+
+// counter counts up on "+", ignores other keys and finishes on Enter, or
+// with errStop on "!". It takes Ctrl+C when catchInterrupt is set.
+type counter struct {
+	n              int
+	draws          int
+	catchInterrupt bool
+}
+
+var errStop = errors.New("stopped")
+
+func (c *counter) Draw(cv *renderer.Canvas, area renderer.Rect) {
+	c.draws++
+	cv.Print(area.X, area.Y, fmt.Sprintf("count %d of %dx%d", c.n, area.Width, area.Height))
+}
+
+func (c *counter) DrawInline(r *renderer.Renderer) {
+	c.draws++
+	r.DrawInline([]string{"inline", fmt.Sprintf("count %d", c.n)})
+}
+
+func (c *counter) Focusable() bool { return true }
+
+func (c *counter) HandleEvent(event *renderer.InputEvent) widget.Result {
+	switch {
+	case event.Rune == '+':
+		c.n++
+		return widget.Handled
+	case event.Rune == '!':
+		return widget.Done(errStop)
+	case event.Key == renderer.KeyEnter:
+		return widget.Done(nil)
+	case event.Key == renderer.KeyCtrlC && c.catchInterrupt:
+		c.n = 0
+		return widget.Handled
+	}
+	return widget.Ignored
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name           string
+		keys           []string
+		catchInterrupt bool
+		err            error
+		count          int
+		draws          int
+	}{
+		{"done", []string{"+", "+", termxtest.KeyEnter}, false, nil, 2, 3},
+		{"done with error", []string{"+", "!"}, false, errStop, 1, 2},
+		{"ignored keys do not redraw", []string{"x", "y", "+", termxtest.KeyEnter}, false, nil, 1, 2},
+		{"interrupt", []string{"+", termxtest.KeyCtrlC}, false, widget.ErrCancelled, 1, 2},
+		{"interrupt handled", []string{"+", termxtest.KeyCtrlC, termxtest.KeyEnter}, true, nil, 0, 3},
+		{"end of input", []string{"+"}, false, io.EOF, 1, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term := termxtest.NewTerminal(30, 4)
+			term.Press(tt.keys...)
+
+			c := &counter{catchInterrupt: tt.catchInterrupt}
+			err := widget.Run(context.Background(), term.Renderer(), c)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Run() error = %v, want %v", err, tt.err)
+			}
+			if c.n != tt.count || c.draws != tt.draws {
+				t.Errorf("count %d after %d draws, want %d after %d", c.n, c.draws, tt.count, tt.draws)
+			}
+		})
+	}
+}
+
+func TestRunRedrawsOnResize(t *testing.T) {
+	term := termxtest.NewTerminal(30, 4)
+	term.Press("+").Resize(40, 6).Press(termxtest.KeyEnter)
+
+	if err := widget.Run(context.Background(), term.Renderer(), &counter{}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	term.AssertLine(t, 0, "count 1 of 40x6")
+}
+
+// A run waiting for keys ends once its context is done
+func TestRunContextCancel(t *testing.T) {
+	in, w := io.Pipe()
+	defer w.Close()
+	r := renderer.NewWithIO(in, io.Discard, func() (int, int, error) { return 30, 4, nil })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- widget.Run(ctx, r, &counter{}) }()
+
+	io.WriteString(w, "+")
+	cancel()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("Run() error = %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not return after the context was cancelled")
+	}
+}
+
+// Input from a pipe makes Run fail with ErrNotTerminal, so that components
+// can fall back to line prompts
+func TestRunNotTerminal(t *testing.T) {
+	in, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	defer w.Close()
+
+	r := renderer.NewWithIO(in, io.Discard, nil)
+	if err := widget.Run(context.Background(), r, &counter{}); err != renderer.ErrNotTerminal {
+		t.Errorf("Run() error = %v, want %v", err, renderer.ErrNotTerminal)
+	}
+}
+
+func TestRunInline(t *testing.T) {
+	term := termxtest.NewTerminal(30, 6)
+	term.Renderer().Write("above\n")
+	term.Press("+", termxtest.KeyEnter)
+
+	c := &counter{}
+	if err := widget.RunInline(context.Background(), term.Renderer(), c); err != nil {
+		t.Fatalf("RunInline() error = %v", err)
+	}
+	if c.draws != 2 {
+		t.Errorf("drawn %d times, want 2", c.draws)
+	}
+	// The region is cleared when the run ends
+	term.AssertLine(t, 0, "above")
+	term.AssertNotContains(t, "inline")
+}
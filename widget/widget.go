@@ -0,0 +1,76 @@
+// Package widget defines the interface shared by the interactive
+// components and the event loop that runs them on a renderer.
+//
+// A widget draws itself into the area it is given and reacts to input
+// events one at a time; the loop owns the terminal, reads the events,
+// dispatches them and redraws when something changed. Because widgets never
+// read input themselves, several of them can share one screen.
+package widget
+
+import (
+	"errors"
+
+	"github.com/vynazevedo/termx/renderer"
+)
+
+// ErrCancelled is returned by Run when the user presses Ctrl+C and no
+// widget handles it
+var ErrCancelled = errors.New("cancelled")
+
+// Widget is an interactive component
+type Widget interface {
+	// Draw draws the widget into area. Coordinates are screen coordinates
+	// and c is clipped to area.
+	Draw(c *renderer.Canvas, area renderer.Rect)
+
+	// HandleEvent updates the widget for an input event. Mouse events carry
+	// screen coordinates, as used by Draw.
+	HandleEvent(event *renderer.InputEvent) Result
+
+	// Focusable reports whether the widget takes keyboard input
+	Focusable() bool
+}
+
+// InlineWidget is implemented by widgets that can also be drawn as a few
+// lines below the previous output, with Renderer.DrawInline, instead of
+// taking over the screen
+type InlineWidget interface {
+	Widget
+	DrawInline(r *renderer.Renderer)
+}
+
+// Result tells the event loop what became of an event
+type Result struct {
+	handled bool
+	done    bool
+	err     error
+}
+
+var (
+	// Ignored means the widget did not use the event, which is then offered
+	// to its parent
+	Ignored = Result{}
+
+	// Handled means the widget used the event and needs to be redrawn
+	Handled = Result{handled: true}
+)
+
+// Done means the widget finished; err is what Run returns
+func Done(err error) Result {
+	return Result{handled: true, done: true, err: err}
+}
+
+// IsHandled reports whether the event was used
+func (r Result) IsHandled() bool {
+	return r.handled
+}
+
+// IsDone reports whether the widget finished
+func (r Result) IsDone() bool {
+	return r.done
+}
+
+// Err returns the error the widget finished with
+func (r Result) Err() error {
+	return r.err
+}